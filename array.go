@@ -0,0 +1,165 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"strconv"
+)
+
+// ErrPointerNotFound is returned when a JSON Pointer does not address a value in the document.
+var ErrPointerNotFound = errors.New("JSON pointer not found")
+
+// DecodeArray returns an iterator over the elements of the JSON array addressed by ptr,
+// unmarshaling each element into a T as it is read.
+// An empty pointer addresses the top-level value.
+// Only one element is held in memory at a time, so arbitrarily large arrays can be processed.
+// Iteration stops at the first error, which is yielded together with the zero value.
+func DecodeArray[T any](dec *jsontext.Decoder, ptr jsontext.Pointer, opts ...json.Options) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		if err := seekPointer(dec, ptr); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		tkn, err := dec.ReadToken()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		if tkn.Kind() != jsontext.KindBeginArray {
+			yield(zero, fmt.Errorf("expected begin array at %q, got %s", ptr, tkn.Kind()))
+			return
+		}
+
+		for dec.PeekKind() != jsontext.KindEndArray {
+			var v T
+			if err := json.UnmarshalDecode(dec, &v, opts...); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+		}
+
+		if _, err := dec.ReadToken(); err != nil { // consume jsontext.KindEndArray
+			yield(zero, err)
+		}
+	}
+}
+
+// ReadFileArray opens a json file and returns an iterator over the elements of the array addressed by ptr.
+// See DecodeArray for details. The file is closed when iteration ends.
+func ReadFileArray[T any](name string, ptr jsontext.Pointer, opts ...json.Options) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		f, err := os.Open(name)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for v, err := range DecodeArray[T](jsontext.NewDecoder(f, opts...), ptr, opts...) {
+			if !yield(v, err) {
+				_ = f.Close()
+				return
+			}
+		}
+
+		if err := f.Close(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// seekPointer advances the decoder so that the next value read is the one addressed by ptr.
+func seekPointer(dec *jsontext.Decoder, ptr jsontext.Pointer) error {
+	if !ptr.IsValid() {
+		return fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+
+	for name := range ptr.Tokens() {
+		switch dec.PeekKind() {
+		case jsontext.KindBeginObject:
+			if err := seekMember(dec, name); err != nil {
+				return err
+			}
+		case jsontext.KindBeginArray:
+			if err := seekElement(dec, name); err != nil {
+				return err
+			}
+		case 0: // invalid, reading the token reports the error
+			_, err := dec.ReadToken()
+			return err
+		default:
+			return fmt.Errorf("%w: %q", ErrPointerNotFound, ptr)
+		}
+	}
+
+	return nil
+}
+
+// seekMember consumes the start of an object and all members up to and including the name of the requested member.
+func seekMember(dec *jsontext.Decoder, name string) error {
+	parent := dec.StackPointer()
+
+	if _, err := dec.ReadToken(); err != nil { // consume jsontext.KindBeginObject
+		return err
+	}
+
+	for dec.PeekKind() != jsontext.KindEndObject {
+		tkn, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+
+		if tkn.String() == name {
+			return nil
+		}
+
+		if err := dec.SkipValue(); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrPointerNotFound, parent.AppendToken(name))
+}
+
+// seekElement consumes the start of an array and all elements before the requested index.
+func seekElement(dec *jsontext.Decoder, index string) error {
+	parent := dec.StackPointer()
+
+	i, err := strconv.ParseUint(index, 10, 0)
+	if err != nil || (len(index) > 1 && index[0] == '0') {
+		return fmt.Errorf("%w: %q", ErrPointerNotFound, parent.AppendToken(index))
+	}
+
+	if _, err := dec.ReadToken(); err != nil { // consume jsontext.KindBeginArray
+		return err
+	}
+
+	for ; i > 0; i-- {
+		if dec.PeekKind() == jsontext.KindEndArray {
+			break
+		}
+
+		if err := dec.SkipValue(); err != nil {
+			return err
+		}
+	}
+
+	if dec.PeekKind() == jsontext.KindEndArray {
+		return fmt.Errorf("%w: %q", ErrPointerNotFound, parent.AppendToken(index))
+	}
+
+	return nil
+}
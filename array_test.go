@@ -0,0 +1,171 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func collectArray[T any](t *testing.T, in string, ptr jsontext.Pointer, opts ...json.Options) ([]T, error) {
+	t.Helper()
+
+	var out []T
+	for v, err := range jsonutil.DecodeArray[T](jsontext.NewDecoder(strings.NewReader(in)), ptr, opts...) {
+		if err != nil {
+			return out, err
+		}
+
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
+func TestDecodeArray(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		ptr  jsontext.Pointer
+		want []int
+	}{
+		{"top-level", `[1,2,3]`, "", []int{1, 2, 3}},
+		{"empty", `[]`, "", nil},
+		{"object member", `{"a":{"skip":[4,5]},"b":[6,7]}`, "/b", []int{6, 7}},
+		{"nested member", `{"a":{"skip":[4,5],"b":[6,7]}}`, "/a/b", []int{6, 7}},
+		{"array element", `[[1],[2,3],[4]]`, "/1", []int{2, 3}},
+		{"escaped name", `{"a/b":[1],"c~d":[2]}`, "/c~0d", []int{2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := collectArray[int](t, tc.in, tc.ptr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("want: %v, got: %v", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		for _, tc := range []struct {
+			in  string
+			ptr jsontext.Pointer
+		}{
+			{`{"a":[1]}`, "/b"},
+			{`[[1],[2]]`, "/2"},
+			{`[[1],[2]]`, "/01"},
+			{`[[1],[2]]`, "/x"},
+			{`{"a":3}`, "/a/b"},
+		} {
+			if _, err := collectArray[int](t, tc.in, tc.ptr); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("%s in %s: expected ErrPointerNotFound, got: %v", tc.ptr, tc.in, err)
+			}
+		}
+	})
+
+	t.Run("invalid pointer", func(t *testing.T) {
+		if _, err := collectArray[int](t, `[1]`, "a"); err == nil {
+			t.Fatalf("expected error")
+		} else if want := `invalid JSON pointer "a"`; err.Error() != want {
+			t.Fatalf("want: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		if _, err := collectArray[int](t, `{"a":{}}`, "/a"); err == nil {
+			t.Fatalf("expected error")
+		} else if want := `expected begin array at "/a", got {`; err.Error() != want {
+			t.Fatalf("want: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("EOF", func(t *testing.T) {
+		errSyn := &jsontext.SyntacticError{}
+
+		got, err := collectArray[int](t, `[1,2,`, "")
+		if err == nil {
+			t.Fatalf("expected error")
+		} else if !errors.As(err, &errSyn) {
+			t.Fatalf("expected error to be a syntactic error, got: %v", err)
+		} else if want := `unexpected EOF`; errSyn.Err.Error() != want {
+			t.Fatalf("expected syntactic error be %s, got: %#v", want, errSyn.Err)
+		}
+
+		if want := []int{1, 2}; !slices.Equal(got, want) {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("not an int", func(t *testing.T) {
+		errSem := &json.SemanticError{}
+
+		if _, err := collectArray[time.Duration](t, `[1,"2"]`, "",
+			json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds)),
+		); err == nil {
+			t.Fatalf("expected error")
+		} else if !errors.As(err, &errSem) {
+			t.Fatalf("expected error to be a semantic error, got: %v", err)
+		} else if tpInt := reflect.TypeFor[int64](); errSem.GoType != tpInt {
+			t.Fatalf("expected semantic error to have type %s, got: %s", tpInt, errSem.GoType)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		var got []int
+		for v, err := range jsonutil.DecodeArray[int](jsontext.NewDecoder(strings.NewReader(`[1,2,3`)), "") {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got = append(got, v); len(got) == 2 {
+				break
+			}
+		}
+
+		if want := []int{1, 2}; !slices.Equal(got, want) {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+}
+
+func TestReadFileArray(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(name, []byte(`{"items":[{"duration":1},{"duration":2}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type item struct {
+		Duration time.Duration `json:"duration"`
+	}
+
+	opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds))
+
+	var got []item
+	for v, err := range jsonutil.ReadFileArray[item](name, "/items", opts) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got = append(got, v)
+	}
+
+	if want := []item{{time.Second}, {2 * time.Second}}; !slices.Equal(got, want) {
+		t.Fatalf("want: %v, got: %v", want, got)
+	}
+
+	for _, err := range jsonutil.ReadFileArray[item](filepath.Join(t.TempDir(), "missing.json"), "") {
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist error, got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"math/big"
+)
+
+// BigIntMarshalString is a custom marshaler for big.Int, marshaling them as strings, e.g. "12345678901234567890",
+// so that consumers that decode JSON numbers as floating-point numbers, e.g. JavaScript, do not lose precision.
+func BigIntMarshalString(enc *jsontext.Encoder, x *big.Int) error {
+	return enc.WriteToken(jsontext.String(x.String()))
+}
+
+// BigIntMarshalNumber is a custom marshaler for big.Int, marshaling them as JSON numbers.
+func BigIntMarshalNumber(enc *jsontext.Encoder, x *big.Int) error {
+	return enc.WriteValue(jsontext.Value(x.String()))
+}
+
+// BigIntUnmarshalStringOrNumber is a custom unmarshaler for big.Int, unmarshaling them from either
+// strings or JSON numbers; null unmarshals as zero.
+// Numbers in exponent notation are accepted if they are integers, e.g. 1e3.
+func BigIntUnmarshalStringOrNumber(dec *jsontext.Decoder, x *big.Int) error {
+	s, err := readNumberText(dec)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		x.SetInt64(0)
+		return nil
+	}
+
+	if _, ok := x.SetString(s, 10); ok {
+		return nil
+	}
+
+	// e.g. 1e3 or 1.0
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || !r.IsInt() {
+		return fmt.Errorf("invalid integer %q", s)
+	}
+
+	x.Set(r.Num())
+	return nil
+}
+
+// BigFloatMarshalString is a custom marshaler for big.Float, marshaling them as strings
+// with the shortest decimal representation that identifies the value, e.g. "0.1" or "1e+100".
+func BigFloatMarshalString(enc *jsontext.Encoder, f *big.Float) error {
+	if f.IsInf() {
+		return fmt.Errorf("cannot marshal infinite big.Float")
+	}
+
+	return enc.WriteToken(jsontext.String(f.Text('g', -1)))
+}
+
+// BigFloatMarshalNumber is a custom marshaler for big.Float, marshaling them as JSON numbers.
+func BigFloatMarshalNumber(enc *jsontext.Encoder, f *big.Float) error {
+	if f.IsInf() {
+		return fmt.Errorf("cannot marshal infinite big.Float")
+	}
+
+	return enc.WriteValue(jsontext.Value(f.Text('g', -1)))
+}
+
+// BigFloatUnmarshalStringOrNumber is a custom unmarshaler for big.Float, unmarshaling them from either
+// strings or JSON numbers; null unmarshals as zero.
+// The precision of the big.Float is kept; if it is zero, it is set to 64.
+func BigFloatUnmarshalStringOrNumber(dec *jsontext.Decoder, f *big.Float) error {
+	s, err := readNumberText(dec)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		f.SetInt64(0)
+		return nil
+	}
+
+	if _, ok := f.SetString(s); !ok {
+		return fmt.Errorf("invalid number %q", s)
+	}
+
+	return nil
+}
+
+// BigRatMarshalString is a custom marshaler for big.Rat, marshaling them as strings
+// of the form "a/b", or "a" if the denominator is one, e.g. "1/3" or "5".
+func BigRatMarshalString(enc *jsontext.Encoder, r *big.Rat) error {
+	return enc.WriteToken(jsontext.String(r.RatString()))
+}
+
+// BigRatMarshalNumber is a custom marshaler for big.Rat, marshaling them as exact JSON numbers, e.g. 0.125.
+// It fails for rationals without a finite decimal representation, e.g. 1/3.
+func BigRatMarshalNumber(enc *jsontext.Encoder, r *big.Rat) error {
+	prec, exact := r.FloatPrec()
+	if !exact {
+		return fmt.Errorf("cannot marshal %s as an exact JSON number", r.RatString())
+	}
+
+	return enc.WriteValue(jsontext.Value(r.FloatString(prec)))
+}
+
+// BigRatUnmarshalStringOrNumber is a custom unmarshaler for big.Rat, unmarshaling them from either
+// strings or JSON numbers; null unmarshals as zero.
+// Strings may be fractions, e.g. "1/3", or decimal numbers, e.g. "0.125".
+func BigRatUnmarshalStringOrNumber(dec *jsontext.Decoder, r *big.Rat) error {
+	s, err := readNumberText(dec)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		r.SetInt64(0)
+		return nil
+	}
+
+	if _, ok := r.SetString(s); !ok {
+		return fmt.Errorf("invalid rational number %q", s)
+	}
+
+	return nil
+}
+
+// readNumberText reads a string, number or null token and returns its text, or "" for null.
+func readNumberText(dec *jsontext.Decoder) (string, error) {
+	tkn, err := dec.ReadToken()
+	if err != nil {
+		return "", err
+	}
+
+	switch tkn.Kind() {
+	case jsontext.KindString, jsontext.KindNumber:
+		if s := tkn.String(); s != "" {
+			return s, nil
+		}
+
+		return "", fmt.Errorf("empty string is not a number")
+	case jsontext.KindNull:
+		return "", nil
+	default:
+		return "", fmt.Errorf("cannot unmarshal JSON %s into a number", tkn.Kind())
+	}
+}
@@ -0,0 +1,216 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestBigInt(t *testing.T) {
+	type testBigInt struct {
+		Value   big.Int  `json:"value"`
+		Pointer *big.Int `json:"pointer"`
+	}
+
+	stringOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.BigIntMarshalString)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BigIntUnmarshalStringOrNumber)),
+	)
+	numberOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.BigIntMarshalNumber)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BigIntUnmarshalStringOrNumber)),
+	)
+
+	x, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+
+	for _, tc := range []struct {
+		name string
+		opts json.Options
+		want string
+	}{
+		{"string", stringOpts, `{"value":"-123456789012345678901234567890","pointer":null}`},
+		{"number", numberOpts, `{"value":-123456789012345678901234567890,"pointer":null}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			in := testBigInt{}
+			in.Value.Set(x)
+
+			b, err := json.Marshal(in, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, b)
+			}
+
+			var out testBigInt
+			if err := json.Unmarshal(b, &out, tc.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.Value.Cmp(x) != 0 || out.Pointer != nil {
+				t.Fatalf("want: %s, got: %+v", x, out)
+			}
+		})
+	}
+
+	t.Run("string or number", func(t *testing.T) {
+		for in, want := range map[string]int64{
+			`"42"`: 42, `42`: 42, `1e3`: 1000, `"1.0"`: 1, `-0`: 0, `null`: 0,
+		} {
+			out := big.NewInt(7)
+			if err := json.Unmarshal([]byte(in), out, stringOpts); err != nil {
+				t.Fatalf("%s: unexpected error: %v", in, err)
+			}
+
+			if out.Int64() != want {
+				t.Fatalf("%s: want: %d, got: %s", in, want, out)
+			}
+		}
+	})
+
+	for _, in := range []string{`"1.5"`, `"abc"`, `""`, `1.5`, `true`, `[]`} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out big.Int
+			if err := json.Unmarshal([]byte(in), &out, stringOpts); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	stringOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.BigFloatMarshalString)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BigFloatUnmarshalStringOrNumber)),
+	)
+	numberOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.BigFloatMarshalNumber)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BigFloatUnmarshalStringOrNumber)),
+	)
+
+	for _, tc := range []struct {
+		name string
+		opts json.Options
+		in   string
+		want string
+	}{
+		{"string", stringOpts, "0.1", `"0.1"`},
+		{"string exponent", stringOpts, "1e100", `"1e+100"`},
+		{"number", numberOpts, "-2.5", `-2.5`},
+		{"number exponent", numberOpts, "1e-100", `1e-100`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, _, err := big.ParseFloat(tc.in, 10, 200, big.ToNearestEven)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(f, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, b)
+			}
+
+			out := new(big.Float).SetPrec(200)
+			if err := json.Unmarshal(b, out, tc.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.Cmp(f) != 0 {
+				t.Fatalf("want: %s, got: %s", f, out)
+			}
+		})
+	}
+
+	t.Run("infinity", func(t *testing.T) {
+		for _, opts := range []json.Options{stringOpts, numberOpts} {
+			if _, err := json.Marshal(new(big.Float).SetInf(false), opts); err == nil {
+				t.Fatalf("expected error")
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var out big.Float
+		if err := json.Unmarshal([]byte(`"one"`), &out, stringOpts); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestBigRat(t *testing.T) {
+	stringOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.BigRatMarshalString)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BigRatUnmarshalStringOrNumber)),
+	)
+	numberOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.BigRatMarshalNumber)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BigRatUnmarshalStringOrNumber)),
+	)
+
+	for _, tc := range []struct {
+		name string
+		opts json.Options
+		in   *big.Rat
+		want string
+	}{
+		{"string fraction", stringOpts, big.NewRat(1, 3), `"1/3"`},
+		{"string integer", stringOpts, big.NewRat(10, 2), `"5"`},
+		{"number", numberOpts, big.NewRat(-1, 8), `-0.125`},
+		{"number integer", numberOpts, big.NewRat(7, 1), `7`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.in, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, b)
+			}
+
+			var out big.Rat
+			if err := json.Unmarshal(b, &out, tc.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.Cmp(tc.in) != 0 {
+				t.Fatalf("want: %s, got: %s", tc.in, &out)
+			}
+		})
+	}
+
+	t.Run("not exact", func(t *testing.T) {
+		if _, err := json.Marshal(big.NewRat(1, 3), numberOpts); err == nil {
+			t.Fatalf("expected error")
+		} else if !strings.Contains(err.Error(), "1/3") {
+			t.Fatalf("expected error to contain 1/3, got: %v", err)
+		}
+	})
+
+	t.Run("decimal string", func(t *testing.T) {
+		var out big.Rat
+		if err := json.Unmarshal([]byte(`"0.1"`), &out, stringOpts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.Cmp(big.NewRat(1, 10)) != 0 {
+			t.Fatalf("want: 1/10, got: %s", &out)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var out big.Rat
+		if err := json.Unmarshal([]byte(`"1/0"`), &out, stringOpts); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package jsonutil
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BinaryEncoding is a text encoding of binary data.
+type BinaryEncoding int
+
+const (
+	// BinaryBase64 is standard base64 with padding as defined in RFC 4648, the default for []byte.
+	BinaryBase64 BinaryEncoding = iota
+	// BinaryBase64URL is URL-safe base64 without padding, e.g. as used in JWTs.
+	// When decoding, padding is accepted.
+	BinaryBase64URL
+	// BinaryBase64RawStd is standard base64 without padding.
+	BinaryBase64RawStd
+	// BinaryBase32 is standard base32 with padding as defined in RFC 4648.
+	BinaryBase32
+	// BinaryHex is lowercase hexadecimal. When decoding, uppercase letters are accepted.
+	BinaryHex
+)
+
+var binaryEncodingNames = [...]string{"base64", "base64url", "base64rawstd", "base32", "hex"}
+
+// String returns the name of the encoding.
+func (e BinaryEncoding) String() string {
+	if e < 0 || int(e) >= len(binaryEncodingNames) {
+		return fmt.Sprintf("BinaryEncoding(%d)", int(e))
+	}
+
+	return binaryEncodingNames[e]
+}
+
+// Encode returns the encoding of b.
+func (e BinaryEncoding) Encode(b []byte) string {
+	switch e {
+	case BinaryBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case BinaryBase64URL:
+		return base64.RawURLEncoding.EncodeToString(b)
+	case BinaryBase64RawStd:
+		return base64.RawStdEncoding.EncodeToString(b)
+	case BinaryBase32:
+		return base32.StdEncoding.EncodeToString(b)
+	case BinaryHex:
+		return hex.EncodeToString(b)
+	default:
+		panic(fmt.Sprintf("jsonutil: unknown binary encoding %d", int(e)))
+	}
+}
+
+// Decode returns the binary data that s encodes.
+func (e BinaryEncoding) Decode(s string) ([]byte, error) {
+	switch e {
+	case BinaryBase64:
+		return base64.StdEncoding.DecodeString(s)
+	case BinaryBase64URL:
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	case BinaryBase64RawStd:
+		return base64.RawStdEncoding.DecodeString(s)
+	case BinaryBase32:
+		return base32.StdEncoding.DecodeString(s)
+	case BinaryHex:
+		return hex.DecodeString(s)
+	default:
+		return nil, fmt.Errorf("unknown binary encoding %d", int(e))
+	}
+}
+
+// lenientBinaryEncodings are the encodings that DecodeBinaryLenient tries, in order.
+var lenientBinaryEncodings = []BinaryEncoding{
+	BinaryHex, BinaryBase32, BinaryBase64, BinaryBase64URL, BinaryBase64RawStd,
+}
+
+// DecodeBinaryLenient decodes s with the first binary encoding that succeeds and returns it.
+// The encodings are tried in the order hex, base32, base64, base64url and base64rawstd,
+// so that a string that is valid in several encodings, e.g. "beef", is decoded as the first one.
+// Base32 precedes base64, since padded base32 is often valid base64 as well,
+// while base64 rarely consists of base32 characters only.
+// Unlike BinaryHex.Decode, only lowercase hex is accepted, so that e.g. "ABCD" is decoded as base64
+// and every string decodes with the encoding that encodes the data to that same string.
+func DecodeBinaryLenient(s string) ([]byte, BinaryEncoding, error) {
+	for _, e := range lenientBinaryEncodings {
+		if e == BinaryHex && strings.ToLower(s) != s {
+			continue
+		}
+
+		if b, err := e.Decode(s); err == nil {
+			return b, e, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("%q is neither hex, base64 nor base32", s)
+}
+
+// BytesMarshal returns a custom marshaler for byte slices, marshaling them as strings with the given encoding,
+// e.g. json.MarshalToFunc(jsonutil.BytesMarshal[[]byte](jsonutil.BinaryHex)).
+// Nil slices are marshaled as empty strings unless json.FormatNilSliceAsNull is set.
+func BytesMarshal[B ~[]byte](e BinaryEncoding) func(*jsontext.Encoder, B) error {
+	e.Encode(nil) // panics early for unknown encodings
+
+	return func(enc *jsontext.Encoder, b B) error {
+		if b == nil {
+			if nullSlices, _ := json.GetOption(enc.Options(), json.FormatNilSliceAsNull); nullSlices {
+				return enc.WriteToken(jsontext.Null)
+			}
+		}
+
+		return enc.WriteToken(jsontext.String(e.Encode(b)))
+	}
+}
+
+// BytesUnmarshal returns a custom unmarshaler for byte slices, unmarshaling them from strings
+// with the given encoding. Nulls unmarshal as nil.
+func BytesUnmarshal[B ~[]byte](e BinaryEncoding) func(*jsontext.Decoder, *B) error {
+	e.Encode(nil) // panics early for unknown encodings
+
+	return func(dec *jsontext.Decoder, b *B) error {
+		s, null, err := readBinaryText(dec)
+		if err != nil || null {
+			*b = nil
+			return err
+		}
+
+		data, err := e.Decode(s)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", e, err)
+		}
+
+		*b = data
+		return nil
+	}
+}
+
+// BytesUnmarshalLenient is a custom unmarshaler for byte slices, unmarshaling them from strings
+// in any of the encodings that DecodeBinaryLenient accepts. Nulls unmarshal as nil.
+func BytesUnmarshalLenient[B ~[]byte](dec *jsontext.Decoder, b *B) error {
+	s, null, err := readBinaryText(dec)
+	if err != nil || null {
+		*b = nil
+		return err
+	}
+
+	data, _, err := DecodeBinaryLenient(s)
+	if err != nil {
+		return err
+	}
+
+	*b = data
+	return nil
+}
+
+// ArrayMarshal returns a custom marshaler for byte arrays, e.g. [32]byte, marshaling them as strings
+// with the given encoding. It panics if A is not a byte array.
+func ArrayMarshal[A any](e BinaryEncoding) func(*jsontext.Encoder, A) error {
+	assertByteArray[A]()
+	e.Encode(nil) // panics early for unknown encodings
+
+	return func(enc *jsontext.Encoder, a A) error {
+		return enc.WriteToken(jsontext.String(e.Encode(arrayBytes(&a))))
+	}
+}
+
+// ArrayUnmarshal returns a custom unmarshaler for byte arrays, e.g. [32]byte, unmarshaling them from strings
+// with the given encoding, which must encode exactly as many bytes as the array holds.
+// Nulls unmarshal as the zero array. It panics if A is not a byte array.
+func ArrayUnmarshal[A any](e BinaryEncoding) func(*jsontext.Decoder, *A) error {
+	assertByteArray[A]()
+	e.Encode(nil) // panics early for unknown encodings
+
+	return func(dec *jsontext.Decoder, a *A) error {
+		return unmarshalArray(dec, a, func(s string) ([]byte, error) {
+			data, err := e.Decode(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", e, err)
+			}
+
+			return data, nil
+		})
+	}
+}
+
+// ArrayUnmarshalLenient is a custom unmarshaler for byte arrays, e.g. [32]byte, unmarshaling them from strings
+// in any of the encodings that DecodeBinaryLenient accepts. Nulls unmarshal as the zero array.
+// It fails if A is not a byte array.
+func ArrayUnmarshalLenient[A any](dec *jsontext.Decoder, a *A) error {
+	if !isByteArray[A]() {
+		return fmt.Errorf("%s is not a byte array", reflect.TypeFor[A]())
+	}
+
+	return unmarshalArray(dec, a, func(s string) ([]byte, error) {
+		data, _, err := DecodeBinaryLenient(s)
+		return data, err
+	})
+}
+
+func unmarshalArray[A any](dec *jsontext.Decoder, a *A, decode func(string) ([]byte, error)) error {
+	s, null, err := readBinaryText(dec)
+	if err != nil || null {
+		*a = *new(A)
+		return err
+	}
+
+	data, err := decode(s)
+	if err != nil {
+		return err
+	}
+
+	dst := arrayBytes(a)
+	if len(data) != len(dst) {
+		return fmt.Errorf("got %d bytes, want %d", len(data), len(dst))
+	}
+
+	copy(dst, data)
+	return nil
+}
+
+func isByteArray[A any]() bool {
+	t := reflect.TypeFor[A]()
+	return t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8
+}
+
+func assertByteArray[A any]() {
+	if !isByteArray[A]() {
+		panic(fmt.Sprintf("jsonutil: %s is not a byte array", reflect.TypeFor[A]()))
+	}
+}
+
+// arrayBytes returns the bytes of the byte array that a points to.
+func arrayBytes[A any](a *A) []byte {
+	return reflect.ValueOf(a).Elem().Bytes()
+}
+
+// readBinaryText reads a string or null token and returns the string or reports whether it read a null.
+func readBinaryText(dec *jsontext.Decoder) (string, bool, error) {
+	tkn, err := dec.ReadToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	switch tkn.Kind() {
+	case jsontext.KindString:
+		return tkn.String(), false, nil
+	case jsontext.KindNull:
+		return "", true, nil
+	default:
+		return "", false, fmt.Errorf("cannot unmarshal JSON %s into binary data", tkn.Kind())
+	}
+}
+
+// Binary is binary data together with the encoding it is marshaled with.
+// It is unmarshaled from strings in any of the encodings that DecodeBinaryLenient accepts
+// and remembers the encoding that matched, so that it is marshaled the way it was received.
+type Binary struct {
+	Data     []byte
+	Encoding BinaryEncoding
+}
+
+// IsZero reports whether there is no data.
+func (b Binary) IsZero() bool { return b.Data == nil }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (b Binary) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if b.Encoding < 0 || int(b.Encoding) >= len(binaryEncodingNames) {
+		return fmt.Errorf("unknown binary encoding %d", int(b.Encoding))
+	}
+
+	return enc.WriteToken(jsontext.String(b.Encoding.Encode(b.Data)))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (b *Binary) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	s, null, err := readBinaryText(dec)
+	if err != nil || null {
+		*b = Binary{}
+		return err
+	}
+
+	data, e, err := DecodeBinaryLenient(s)
+	if err != nil {
+		return err
+	}
+
+	*b = Binary{Data: data, Encoding: e}
+	return nil
+}
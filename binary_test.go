@@ -0,0 +1,329 @@
+package jsonutil_test
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testSignature []byte
+
+func TestBinaryEncoding(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0x01, 0x02}
+
+	for _, tc := range []struct {
+		e    jsonutil.BinaryEncoding
+		want string
+	}{
+		{jsonutil.BinaryBase64, "+/8BAg=="},
+		{jsonutil.BinaryBase64URL, "-_8BAg"},
+		{jsonutil.BinaryBase64RawStd, "+/8BAg"},
+		{jsonutil.BinaryBase32, "7P7QCAQ="},
+		{jsonutil.BinaryHex, "fbff0102"},
+	} {
+		t.Run(tc.e.String(), func(t *testing.T) {
+			if got := tc.e.Encode(data); got != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, got)
+			}
+
+			got, err := tc.e.Decode(tc.want)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Fatalf("want: %x, got: %x", data, got)
+			}
+
+			b, e, err := jsonutil.DecodeBinaryLenient(tc.want)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(b, data) || e != tc.e {
+				t.Fatalf("want: %x (%s), got: %x (%s)", data, tc.e, b, e)
+			}
+		})
+	}
+
+	t.Run("padded base64url", func(t *testing.T) {
+		if got, err := jsonutil.BinaryBase64URL.Decode("-_8BAg=="); err != nil || !bytes.Equal(got, data) {
+			t.Fatalf("want: %x, got: %x (%v)", data, got, err)
+		}
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		if _, e, err := jsonutil.DecodeBinaryLenient("beef"); err != nil || e != jsonutil.BinaryHex {
+			t.Fatalf("want: hex, got: %s (%v)", e, err)
+		}
+
+		// uppercase hex would not be encoded the same way
+		b, e, err := jsonutil.DecodeBinaryLenient("ABCD")
+		if err != nil || e != jsonutil.BinaryBase64 || !bytes.Equal(b, []byte{0x00, 0x10, 0x83}) {
+			t.Fatalf("want: 001083 (base64), got: %x (%s, %v)", b, e, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, _, err := jsonutil.DecodeBinaryLenient("not binary!"); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if _, err := jsonutil.BinaryEncoding(-1).Decode(""); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if s := jsonutil.BinaryEncoding(9).String(); s != "BinaryEncoding(9)" {
+			t.Fatalf("unexpected name: %s", s)
+		}
+	})
+}
+
+func TestBytes(t *testing.T) {
+	type testBytes struct {
+		Data      []byte        `json:"data"`
+		Nil       []byte        `json:"nil"`
+		Signature testSignature `json:"signature"`
+	}
+
+	opts := json.JoinOptions(
+		json.WithMarshalers(json.JoinMarshalers(
+			json.MarshalToFunc(jsonutil.BytesMarshal[[]byte](jsonutil.BinaryBase64URL)),
+			json.MarshalToFunc(jsonutil.BytesMarshal[testSignature](jsonutil.BinaryHex)),
+		)),
+		json.WithUnmarshalers(json.JoinUnmarshalers(
+			json.UnmarshalFromFunc(jsonutil.BytesUnmarshal[[]byte](jsonutil.BinaryBase64URL)),
+			json.UnmarshalFromFunc(jsonutil.BytesUnmarshal[testSignature](jsonutil.BinaryHex)),
+		)),
+	)
+
+	v := testBytes{Data: []byte{0xfb, 0xff}, Signature: testSignature{0xde, 0xad}}
+
+	b, err := json.Marshal(v, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"data":"-_8","nil":"","signature":"dead"}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	out := testBytes{Nil: []byte{1}}
+	if err := json.Unmarshal([]byte(`{"data":"-_8","nil":null,"signature":"DEAD"}`), &out, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(out.Data, v.Data) || out.Nil != nil || !bytes.Equal(out.Signature, v.Signature) {
+		t.Fatalf("want: %+v, got: %+v", v, out)
+	}
+
+	t.Run("nil as null", func(t *testing.T) {
+		b, err := json.Marshal(testBytes{}, opts, json.FormatNilSliceAsNull(true))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"data":null,"nil":null,"signature":null}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	for _, in := range []string{`{"signature":"xyz"}`, `{"data":"+/8"}`, `{"data":1}`} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out testBytes
+			if err := json.Unmarshal([]byte(in), &out, opts); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BytesUnmarshalLenient[[]byte]))
+
+		var out []string
+		for _, in := range []string{`"fbff"`, `"+/8="`, `"-_8"`, `"+/8"`, `"7P7Q===="`} {
+			var b []byte
+			if err := json.Unmarshal([]byte(in), &b, opts); err != nil {
+				t.Fatalf("%s: unexpected error: %v", in, err)
+			}
+
+			out = append(out, jsonutil.BinaryHex.Encode(b))
+		}
+
+		if got := strings.Join(out, " "); got != "fbff fbff fbff fbff fbff" {
+			t.Fatalf("want all to be fbff, got: %s", got)
+		}
+
+		var b []byte
+		if err := json.Unmarshal([]byte(`"not binary!"`), &b, opts); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic")
+			}
+		}()
+
+		jsonutil.BytesMarshal[[]byte](jsonutil.BinaryEncoding(-1))
+	})
+}
+
+func TestArray(t *testing.T) {
+	type testArray struct {
+		Key  [4]byte  `json:"key"`
+		Hash *[2]byte `json:"hash"`
+	}
+
+	opts := json.JoinOptions(
+		json.WithMarshalers(json.JoinMarshalers(
+			json.MarshalToFunc(jsonutil.ArrayMarshal[[4]byte](jsonutil.BinaryHex)),
+			json.MarshalToFunc(jsonutil.ArrayMarshal[[2]byte](jsonutil.BinaryBase32)),
+		)),
+		json.WithUnmarshalers(json.JoinUnmarshalers(
+			json.UnmarshalFromFunc(jsonutil.ArrayUnmarshal[[4]byte](jsonutil.BinaryHex)),
+			json.UnmarshalFromFunc(jsonutil.ArrayUnmarshalLenient[[2]byte]),
+		)),
+	)
+
+	v := testArray{Key: [4]byte{0xde, 0xad, 0xbe, 0xef}, Hash: &[2]byte{0xfb, 0xff}}
+
+	b, err := json.Marshal(v, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"key":"deadbeef","hash":"7P7Q===="}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out testArray
+	if err := json.Unmarshal(b, &out, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Key != v.Key || out.Hash == nil || *out.Hash != *v.Hash {
+		t.Fatalf("want: %+v, got: %+v", v, out)
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		var out testArray
+		if err := json.Unmarshal([]byte(`{"hash":"-_8"}`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if *out.Hash != *v.Hash {
+			t.Fatalf("want: %x, got: %x", *v.Hash, *out.Hash)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		out := v
+		if err := json.Unmarshal([]byte(`{"key":null}`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.Key != [4]byte{} {
+			t.Fatalf("want: zero array, got: %x", out.Key)
+		}
+	})
+
+	for _, in := range []string{`{"key":"dead"}`, `{"key":"deadbeef00"}`, `{"key":"zz"}`, `{"hash":"fb"}`} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out testArray
+			if err := json.Unmarshal([]byte(in), &out, opts); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+
+	t.Run("not a byte array", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic")
+			}
+		}()
+
+		jsonutil.ArrayMarshal[[4]int](jsonutil.BinaryHex)
+	})
+
+	t.Run("lenient not a byte array", func(t *testing.T) {
+		var out [2]int
+		if err := json.Unmarshal([]byte(`"fbff"`), &out,
+			json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.ArrayUnmarshalLenient[[2]int]))); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestBinary(t *testing.T) {
+	type testWebhook struct {
+		Signature jsonutil.Binary `json:"signature"`
+		Optional  jsonutil.Binary `json:"optional,omitzero"`
+	}
+
+	for _, in := range []string{`"fbff"`, `"-_8"`, `"7P7Q===="`, `"+/8="`} {
+		t.Run(in, func(t *testing.T) {
+			var out testWebhook
+			if err := json.Unmarshal([]byte(`{"signature":`+in+`}`), &out); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(out.Signature.Data, []byte{0xfb, 0xff}) {
+				t.Fatalf("want: fbff, got: %x", out.Signature.Data)
+			}
+
+			b, err := json.Marshal(out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want := `{"signature":` + in + `}`; string(b) != want {
+				t.Fatalf("want: %s, got: %s", want, b)
+			}
+		})
+	}
+
+	t.Run("uppercase", func(t *testing.T) {
+		var out testWebhook
+		if err := json.Unmarshal([]byte(`{"signature":"ABCD"}`), &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		b, err := json.Marshal(out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"signature":"ABCD"}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		out := testWebhook{Signature: jsonutil.Binary{Data: []byte{1}}}
+		if err := json.Unmarshal([]byte(`{"signature":null}`), &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !out.Signature.IsZero() {
+			t.Fatalf("expected zero, got: %+v", out.Signature)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var out testWebhook
+		if err := json.Unmarshal([]byte(`{"signature":"not binary!"}`), &out); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if _, err := json.Marshal(jsonutil.Binary{Encoding: -1}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
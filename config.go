@@ -0,0 +1,261 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ConfigLoader describes the layers of a configuration.
+// Files are deep-merged in order following the JSON Merge Patch semantics of RFC 7396,
+// i.e. objects are merged member by member, null removes a member and all other values,
+// including arrays, replace what was there before.
+// Environment variable overrides are applied on top of the merged files.
+type ConfigLoader struct {
+	// Files are the json files that are merged in order, later files take precedence.
+	Files []string
+	// AllowMissing skips files that don't exist instead of failing.
+	AllowMissing bool
+	// Env maps environment variable names to the value they override,
+	// either as a JSON Pointer (e.g. "/server/port") or as a dotted path of json names (e.g. "server.port").
+	// A variable whose content is valid JSON is applied as that JSON value,
+	// unless the addressed field of T is a string, otherwise it is applied as a string.
+	// Overrides are applied in order of the variable names.
+	Env map[string]string
+	// LookupEnv looks up environment variables. If nil, os.LookupEnv is used.
+	LookupEnv func(string) (string, bool)
+}
+
+// ConfigSources maps the JSON Pointers of the configured values to the layer that supplied them.
+// Layers are named by their file name or, for environment variables, by "$" followed by the variable name.
+type ConfigSources map[jsontext.Pointer]string
+
+// Source returns the layer that supplied the value at ptr.
+// If the value was supplied as part of a larger value, e.g. an array, the layer of that value is returned.
+func (s ConfigSources) Source(ptr jsontext.Pointer) (string, bool) {
+	for {
+		if layer, ok := s[ptr]; ok {
+			return layer, true
+		}
+
+		if ptr == "" {
+			return "", false
+		}
+
+		ptr = ptr.Parent()
+	}
+}
+
+// LoadConfig loads the configuration described by the loader and unmarshals it into a T.
+// It also returns which layer supplied each value.
+func LoadConfig[T any](l ConfigLoader, opts ...json.Options) (T, ConfigSources, error) {
+	var (
+		v       T
+		doc     jsontext.Value
+		sources = ConfigSources{}
+	)
+
+	for _, name := range l.Files {
+		layer, err := ReadFile[jsontext.Value](name, opts...)
+		if err != nil {
+			if l.AllowMissing && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return v, nil, err
+		}
+
+		if doc, err = mergePatch(doc, layer, "", sources.observe(name)); err != nil {
+			return v, nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	lookupEnv := l.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(l.Env)) {
+		val, ok := lookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		ptr, err := configPointer(l.Env[key])
+		if err != nil {
+			return v, nil, fmt.Errorf("$%s: %w", key, err)
+		}
+
+		override := jsontext.Value(val)
+		if tp := typeAtPointer(reflect.TypeFor[T](), ptr); (tp != nil && tp.Kind() == reflect.String) ||
+			!override.IsValid() {
+			if override, err = json.Marshal(val); err != nil {
+				return v, nil, fmt.Errorf("$%s: %w", key, err)
+			}
+		}
+
+		if doc, err = modifyPointer(doc, ptr, override, opForce); err != nil {
+			return v, nil, fmt.Errorf("$%s: %w", key, err)
+		}
+
+		deleteSources(sources, ptr)
+		if err := recordSources(sources, override, ptr, "$"+key); err != nil {
+			return v, nil, fmt.Errorf("$%s: %w", key, err)
+		}
+	}
+
+	if doc == nil {
+		doc = jsontext.Value("null")
+	}
+
+	if err := json.Unmarshal(doc, &v, opts...); err != nil {
+		return v, sources, err
+	}
+
+	return v, sources, nil
+}
+
+// configPointer converts a JSON Pointer or a dotted path of json names to a JSON Pointer.
+func configPointer(path string) (jsontext.Pointer, error) {
+	if path == "" || path[0] == '/' {
+		ptr := jsontext.Pointer(path)
+		if !ptr.IsValid() {
+			return "", fmt.Errorf("invalid JSON pointer %q", path)
+		}
+
+		return ptr, nil
+	}
+
+	var ptr jsontext.Pointer
+	for name := range strings.SplitSeq(path, ".") {
+		ptr = ptr.AppendToken(name)
+	}
+
+	return ptr, nil
+}
+
+// observe returns a mergeObserver that records the layer of the values a merge patch sets.
+func (s ConfigSources) observe(layer string) mergeObserver {
+	return func(ptr jsontext.Pointer, v jsontext.Value) {
+		deleteSources(s, ptr)
+		if v != nil {
+			s[ptr] = layer
+		}
+	}
+}
+
+// recordSources records the layer for every leaf value of v.
+func recordSources(sources ConfigSources, v jsontext.Value, ptr jsontext.Pointer, layer string) error {
+	if v.Kind() != jsontext.KindBeginObject {
+		sources[ptr] = layer
+		return nil
+	}
+
+	members, err := readMembers(v)
+	if err != nil {
+		return err
+	}
+
+	if len(members) == 0 {
+		sources[ptr] = layer
+		return nil
+	}
+
+	for _, m := range members {
+		if err := recordSources(sources, m.value, ptr.AppendToken(m.name), layer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteSources removes the recorded layers of the value at ptr and all values it contains.
+func deleteSources(sources ConfigSources, ptr jsontext.Pointer) {
+	for p := range sources {
+		if ptr.Contains(p) {
+			delete(sources, p)
+		}
+	}
+}
+
+// typeAtPointer returns the Go type of the value at ptr within a value of type t, or nil if it can't be determined.
+func typeAtPointer(t reflect.Type, ptr jsontext.Pointer) reflect.Type {
+	for name := range ptr.Tokens() {
+		switch t = indirectType(t); t.Kind() {
+		case reflect.Struct:
+			f, ok := fieldByJSONName(t, name)
+			if !ok {
+				return nil
+			}
+
+			t = f.Type
+		case reflect.Map, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+
+	return indirectType(t)
+}
+
+// indirectType returns the type that t points to, following any number of pointers.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// fieldByJSONName returns the struct field that is represented by the given JSON object member name.
+// Fields of the struct itself take precedence over fields of embedded structs.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	var embedded []reflect.Type
+
+	for f := range t.Fields() {
+		tag, hasTag := f.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+
+		jsonName, opts, _ := strings.Cut(tag, ",")
+		isEmbed := slices.ContainsFunc(strings.Split(opts, ","), func(opt string) bool {
+			return opt == "embed" || opt == "inline"
+		})
+
+		if isEmbed || (f.Anonymous && jsonName == "") {
+			if ft := indirectType(f.Type); ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if !hasTag || jsonName == "" {
+			jsonName = f.Name
+		}
+
+		if jsonName == name {
+			return f, true
+		}
+	}
+
+	for _, et := range embedded {
+		if f, ok := fieldByJSONName(et, name); ok {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
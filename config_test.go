@@ -0,0 +1,206 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestLoadConfig(t *testing.T) {
+	type server struct {
+		Host    string        `json:"host"`
+		Port    int           `json:"port"`
+		Timeout time.Duration `json:"timeout"`
+	}
+
+	type config struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		Server  server   `json:"server"`
+		Tags    []string `json:"tags"`
+		Debug   bool     `json:"debug,omitzero"`
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+
+		name = filepath.Join(dir, name)
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return name
+	}
+
+	base := write("base.json", `{
+	"name": "service",
+	"server": {"host": "localhost", "port": 80, "timeout": 10},
+	"tags": ["a", "b"],
+	"debug": true
+}`)
+	prod := write("prod.json", `{"server": {"host": "example.com"}, "tags": ["c"], "debug": null}`)
+	missing := filepath.Join(dir, "missing.json")
+
+	jsonOpts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds))
+
+	env := map[string]string{
+		"PORT":    "8080",
+		"VERSION": "1.0",
+		"TIMEOUT": "30",
+		"TAG":     "d",
+	}
+
+	loader := jsonutil.ConfigLoader{
+		Files:        []string{base, missing, prod},
+		AllowMissing: true,
+		Env: map[string]string{
+			"PORT":    "/server/port",
+			"VERSION": "version",
+			"TIMEOUT": "server.timeout",
+			"TAG":     "/tags/0",
+			"UNSET":   "name",
+		},
+		LookupEnv: func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		},
+	}
+
+	got, sources, err := jsonutil.LoadConfig[config](loader, jsonOpts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := config{
+		Name:    "service",
+		Version: "1.0",
+		Server:  server{Host: "example.com", Port: 8080, Timeout: 30 * time.Second},
+		Tags:    []string{"d"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want: %+v, got: %+v", want, got)
+	}
+
+	wantSources := jsonutil.ConfigSources{
+		"/name":           base,
+		"/version":        "$VERSION",
+		"/server/host":    prod,
+		"/server/port":    "$PORT",
+		"/server/timeout": "$TIMEOUT",
+		"/tags":           prod,
+		"/tags/0":         "$TAG",
+	}
+	if !maps.Equal(sources, wantSources) {
+		t.Fatalf("want: %v, got: %v", wantSources, sources)
+	}
+
+	for ptr, want := range map[jsontext.Pointer]string{
+		"/server/port": "$PORT",
+		"/tags/1":      prod,
+		"/debug":       "",
+	} {
+		if got, _ := sources.Source(ptr); got != want {
+			t.Fatalf("%s: want source %q, got: %q", ptr, want, got)
+		}
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, _, err := jsonutil.LoadConfig[config](jsonutil.ConfigLoader{
+			Files: []string{base, missing},
+		}); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist error, got: %v", err)
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		broken := write("broken.json", `{"name":`)
+		errSyn := &jsontext.SyntacticError{}
+
+		if _, _, err := jsonutil.LoadConfig[config](jsonutil.ConfigLoader{
+			Files: []string{base, broken},
+		}); !errors.As(err, &errSyn) {
+			t.Fatalf("expected error to be a syntactic error, got: %v", err)
+		}
+	})
+
+	t.Run("semantic error", func(t *testing.T) {
+		errSem := &json.SemanticError{}
+
+		if _, sources, err := jsonutil.LoadConfig[config](jsonutil.ConfigLoader{
+			Files:     []string{base},
+			Env:       map[string]string{"PORT": "server.port"},
+			LookupEnv: func(string) (string, bool) { return "http", true },
+		}, jsonOpts); !errors.As(err, &errSem) {
+			t.Fatalf("expected error to be a semantic error, got: %v", err)
+		} else if layer, _ := sources.Source(errSem.JSONPointer); layer != "$PORT" {
+			t.Fatalf("expected error to be caused by $PORT, got: %q", layer)
+		}
+	})
+
+	t.Run("invalid pointer", func(t *testing.T) {
+		if _, _, err := jsonutil.LoadConfig[config](jsonutil.ConfigLoader{
+			Env:       map[string]string{"PORT": "/server/port~"},
+			LookupEnv: func(string) (string, bool) { return "80", true },
+		}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		if _, _, err := jsonutil.LoadConfig[config](jsonutil.ConfigLoader{
+			Files:     []string{base},
+			Env:       map[string]string{"TAG": "tags.5"},
+			LookupEnv: func(string) (string, bool) { return "x", true },
+		}); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+			t.Fatalf("expected ErrPointerNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("same merge as MergePatch", func(t *testing.T) {
+		first := write("first.json", `{"b":1,"a":{"y":1,"x":2},"d":[1]}`)
+		second := write("second.json", `{"a":{"x":3,"y":null},"c":4,"d":{"e":5}}`)
+
+		got, _, err := jsonutil.LoadConfig[jsontext.Value](jsonutil.ConfigLoader{Files: []string{first, second}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want, err := jsonutil.MergePatch(jsontext.Value(`{"b":1,"a":{"y":1,"x":2},"d":[1]}`),
+			jsontext.Value(`{"a":{"x":3,"y":null},"c":4,"d":{"e":5}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(got) != string(want) || string(got) != `{"b":1,"a":{"x":3},"d":{"e":5},"c":4}` {
+			t.Fatalf("want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		got, sources, err := jsonutil.LoadConfig[config](jsonutil.ConfigLoader{
+			Env:       map[string]string{"NAME": "name", "TAGS": "tags"},
+			LookupEnv: func(key string) (string, bool) { return map[string]string{"NAME": "true", "TAGS": `["x"]`}[key], true },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.Name != "true" || !slices.Equal(got.Tags, []string{"x"}) {
+			t.Fatalf("unexpected config: %+v", got)
+		}
+
+		if want := (jsonutil.ConfigSources{"/name": "$NAME", "/tags": "$TAGS"}); !maps.Equal(sources, want) {
+			t.Fatalf("want: %v, got: %v", want, sources)
+		}
+	})
+}
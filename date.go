@@ -20,6 +20,7 @@ func DateMarshalIntUnix(enc *jsontext.Encoder, d civil.Date) error {
 }
 
 // DateUnmarshalIntUnix is a custom unmarshaler for civil.Date, unmarshaling them from integers and assuming they represent unix time.
+// The date is the date in UTC, matching DateMarshalIntUnix, regardless of the local time zone.
 func DateUnmarshalIntUnix(dec *jsontext.Decoder, d *civil.Date) error {
 	var seconds int64
 	if err := json.UnmarshalDecode(dec, &seconds); err != nil {
@@ -29,8 +30,46 @@ func DateUnmarshalIntUnix(dec *jsontext.Decoder, d *civil.Date) error {
 	if seconds == 0 {
 		*d = civil.Date{}
 	} else {
-		*d = civil.DateOf(time.Unix(seconds, 0))
+		*d = civil.DateOf(time.Unix(seconds, 0).UTC())
 	}
 
 	return nil
 }
+
+// DateMarshalString is a custom marshaler for civil.Date, marshaling them as strings like "2006-01-02".
+func DateMarshalString(enc *jsontext.Encoder, d civil.Date) error {
+	return enc.WriteToken(jsontext.String(d.String()))
+}
+
+// DateUnmarshalString is a custom unmarshaler for civil.Date, unmarshaling them from strings like "2006-01-02".
+func DateUnmarshalString(dec *jsontext.Decoder, d *civil.Date) error {
+	var s string
+	if err := json.UnmarshalDecode(dec, &s); err != nil {
+		return err
+	}
+
+	parsed, err := civil.ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// ISODate is a civil.Date that is marshaled as an ISO 8601 string like "2006-01-02",
+// see DateMarshalString and DateUnmarshalString.
+type ISODate civil.Date
+
+// IsZero reports whether the date is the zero date.
+func (d ISODate) IsZero() bool { return civil.Date(d).IsZero() }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (d ISODate) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return DateMarshalString(enc, civil.Date(d))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (d *ISODate) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return DateUnmarshalString(dec, (*civil.Date)(d))
+}
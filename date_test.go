@@ -5,6 +5,7 @@ import (
 	"encoding/json/v2"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 	"strconv"
 	"testing"
@@ -12,6 +13,7 @@ import (
 
 	"cloud.google.com/go/civil"
 	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
 )
 
 func TestUnixDate(t *testing.T) {
@@ -60,6 +62,21 @@ func TestUnixDate(t *testing.T) {
 		}
 	})
 
+	t.Run("local time zone", func(t *testing.T) {
+		local := time.Local
+		time.Local = time.FixedZone("UTC-5", -5*60*60)
+		defer func() { time.Local = local }()
+
+		var out testDate
+		if err := json.Unmarshal([]byte(`{"date":1704153600}`), &out, jsonOpts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := (civil.Date{Year: 2024, Month: time.January, Day: 2}); out.Date != want {
+			t.Fatalf("want: %s, got: %s", want, out.Date)
+		}
+	})
+
 	now := time.Now().UTC().Truncate(time.Hour * 24)
 	today := civil.DateOf(now)
 	todayUnix := now.Unix()
@@ -114,3 +131,80 @@ func TestUnixDate(t *testing.T) {
 		})
 	}
 }
+
+var unixDateCodec = jsontest.Codec[civil.Date]{
+	Marshal:   jsonutil.DateMarshalIntUnix,
+	Unmarshal: jsonutil.DateUnmarshalIntUnix,
+	// 1970-01-01 is encoded as 0 like the zero date
+	Equal: func(a, b civil.Date) bool {
+		epoch := civil.Date{Year: 1970, Month: time.January, Day: 1}
+		if a == epoch {
+			a = civil.Date{}
+		}
+
+		if b == epoch {
+			b = civil.Date{}
+		}
+
+		return a == b
+	},
+}
+
+func TestUnixDateRoundTrip(t *testing.T) {
+	jsontest.CheckRoundTrip(t, unixDateCodec, func(r *rand.Rand) civil.Date {
+		return civil.Date{Year: 1 + r.IntN(9999), Month: time.Month(1 + r.IntN(12)), Day: 1 + r.IntN(28)}
+	}, 1000)
+}
+
+func FuzzUnixDate(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, unixDateCodec, `0`, `1`, `-1`, `86400`, `1700000000`, `-62135596800`, `null`)
+}
+
+var stringDateCodec = jsontest.Codec[civil.Date]{
+	Marshal:   jsonutil.DateMarshalString,
+	Unmarshal: jsonutil.DateUnmarshalString,
+}
+
+func TestStringDateRoundTrip(t *testing.T) {
+	jsontest.CheckRoundTrip(t, stringDateCodec, func(r *rand.Rand) civil.Date {
+		return civil.Date{Year: 1 + r.IntN(9999), Month: time.Month(1 + r.IntN(12)), Day: 1 + r.IntN(28)}
+	}, 1000)
+}
+
+func FuzzStringDate(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, stringDateCodec, `"2024-01-02"`, `"0001-01-01"`, `"2024-02-30"`, `"-0001-01-01"`, `0`, `null`)
+}
+
+func TestISODate(t *testing.T) {
+	type dates struct {
+		Date     jsonutil.ISODate  `json:"date"`
+		Pointer  *jsonutil.ISODate `json:"pointer,omitempty"`
+		OmitZero jsonutil.ISODate  `json:"omitZero,omitzero"`
+	}
+
+	d := jsonutil.ISODate{Year: 2024, Month: time.January, Day: 2}
+
+	b, err := json.Marshal(dates{Date: d, Pointer: &d})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"date":"2024-01-02","pointer":"2024-01-02"}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out dates
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Date != d || *out.Pointer != d || !out.OmitZero.IsZero() {
+		t.Fatalf("want: %v, got: %+v", d, out)
+	}
+
+	for _, in := range []string{`{"date":"2024-13-01"}`, `{"date":20240102}`} {
+		if err := json.Unmarshal([]byte(in), &out); err == nil {
+			t.Fatalf("expected error for %s", in)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DiffKind is the kind of a Difference.
+type DiffKind int
+
+const (
+	// DiffAdded means that a value is only present in the second document.
+	DiffAdded DiffKind = iota + 1
+	// DiffRemoved means that a value is only present in the first document.
+	DiffRemoved
+	// DiffChanged means that a value differs between the documents, but is of the same type.
+	DiffChanged
+	// DiffTypeChanged means that a value is of a different type in the documents, e.g. a string and a number.
+	DiffTypeChanged
+)
+
+// String returns the name of the kind.
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	case DiffTypeChanged:
+		return "type-changed"
+	default:
+		return "DiffKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Difference is a difference between two JSON documents.
+type Difference struct {
+	// Pointer references the value that differs.
+	// For added values, it refers to the second document, otherwise to the first one.
+	Pointer jsontext.Pointer
+	// Kind is the kind of the difference.
+	Kind DiffKind
+	// Old is the value in the first document, or nil if it was added.
+	Old jsontext.Value
+	// New is the value in the second document, or nil if it was removed.
+	New jsontext.Value
+}
+
+// Differences is a list of differences between two JSON documents.
+type Differences []Difference
+
+// String renders the differences in a unified-diff-like text format, e.g.
+//
+//	@@ /server/port @@
+//	- 80
+//	+ 8080
+func (d Differences) String() string {
+	var sb strings.Builder
+	for _, diff := range d {
+		fmt.Fprintf(&sb, "@@ %s @@\n", diff.Pointer)
+
+		if diff.Old != nil {
+			fmt.Fprintf(&sb, "- %s\n", diff.Old)
+		}
+
+		if diff.New != nil {
+			fmt.Fprintf(&sb, "+ %s\n", diff.New)
+		}
+	}
+
+	return sb.String()
+}
+
+// DiffOption configures Diff.
+type DiffOption func(*differ) error
+
+// IgnoreKeyOrder makes Diff ignore the order of object members.
+// Without it, a different order of the members both objects have is reported as a change of the object.
+func IgnoreKeyOrder() DiffOption {
+	return func(d *differ) error {
+		d.ignoreKeyOrder = true
+		return nil
+	}
+}
+
+// IgnoreArrayOrder makes Diff ignore the order of the elements of the arrays referenced by the given JSON Pointers.
+// A reference token "*" matches any object member or array element, e.g. "/users/*/roles".
+func IgnoreArrayOrder(paths ...string) DiffOption {
+	return func(d *differ) error {
+		for _, path := range paths {
+			p, err := pointerTokensOf(jsontext.Pointer(path))
+			if err != nil {
+				return err
+			}
+
+			d.unorderedArrays = append(d.unorderedArrays, p)
+		}
+
+		return nil
+	}
+}
+
+// IgnoreNumberFormat makes Diff compare numbers by their value instead of their representation,
+// e.g. 1, 1.0 and 1e0 are considered equal. Numbers are compared exactly, without rounding them to float64.
+func IgnoreNumberFormat() DiffOption {
+	return func(d *differ) error {
+		d.ignoreNumberFormat = true
+		return nil
+	}
+}
+
+// Diff returns the structural differences between two JSON documents.
+// Objects are compared member by member, arrays element by element after aligning them so that
+// inserted or removed elements don't show up as changes of all following elements.
+// Strings are compared after unescaping.
+func Diff(a, b jsontext.Value, opts ...DiffOption) (Differences, error) {
+	if !a.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", a)
+	}
+
+	if !b.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", b)
+	}
+
+	d := &differ{}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.diff("", a, b); err != nil {
+		return nil, err
+	}
+
+	return d.diffs, nil
+}
+
+type differ struct {
+	ignoreKeyOrder     bool
+	ignoreNumberFormat bool
+	unorderedArrays    []pointerTokens
+
+	diffs Differences
+}
+
+func (d *differ) add(ptr jsontext.Pointer, kind DiffKind, old, new jsontext.Value) {
+	if old != nil {
+		old = compactValue(old)
+	}
+
+	if new != nil {
+		new = compactValue(new)
+	}
+
+	d.diffs = append(d.diffs, Difference{Pointer: ptr, Kind: kind, Old: old, New: new})
+}
+
+// key returns a text that is the same for two values at ptr if and only if Diff reports no difference between them.
+// Comparing keys is much cheaper than comparing the values, which is what aligning arrays needs.
+func (d *differ) key(ptr jsontext.Pointer, v jsontext.Value) (string, error) {
+	b, err := d.appendKey(nil, ptr, v)
+	return string(b), err
+}
+
+func (d *differ) appendKey(b []byte, ptr jsontext.Pointer, v jsontext.Value) ([]byte, error) {
+	switch v.Kind() {
+	case jsontext.KindBeginObject:
+		members, err := readMembers(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if d.ignoreKeyOrder {
+			slices.SortFunc(members, func(a, b member) int { return strings.Compare(a.name, b.name) })
+		}
+
+		b = append(b, '{')
+		for i, m := range members {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if b, err = jsontext.AppendQuote(b, m.name); err != nil {
+				return nil, err
+			}
+
+			b = append(b, ':')
+			if b, err = d.appendKey(b, ptr.AppendToken(m.name), m.value); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(b, '}'), nil
+	case jsontext.KindBeginArray:
+		var elems []jsontext.Value
+		if err := json.Unmarshal(v, &elems); err != nil {
+			return nil, err
+		}
+
+		keys, err := d.keys(ptr, elems)
+		if err != nil {
+			return nil, err
+		}
+
+		if d.isUnordered(ptr) {
+			slices.Sort(keys)
+		}
+
+		return append(append(append(b, '['), strings.Join(keys, ",")...), ']'), nil
+	case jsontext.KindString:
+		return appendStringKey(b, v)
+	case jsontext.KindNumber:
+		if d.ignoreNumberFormat {
+			return appendNumberKey(b, v)
+		}
+
+		return append(b, bytes.TrimSpace(v)...), nil
+	default: // literals
+		return append(b, bytes.TrimSpace(v)...), nil
+	}
+}
+
+// keys returns the keys of the elements of the array at ptr.
+func (d *differ) keys(ptr jsontext.Pointer, elems []jsontext.Value) ([]string, error) {
+	keys := make([]string, len(elems))
+	for i, elem := range elems {
+		var err error
+		if keys[i], err = d.key(ptr.AppendToken(strconv.Itoa(i)), elem); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// isUnordered reports whether the order of the elements of the array at ptr is ignored.
+func (d *differ) isUnordered(ptr jsontext.Pointer) bool {
+	return slices.ContainsFunc(d.unorderedArrays, func(pattern pointerTokens) bool { return pattern.matches(ptr) })
+}
+
+func (d *differ) diff(ptr jsontext.Pointer, a, b jsontext.Value) error {
+	kindA, kindB := valueType(a.Kind()), valueType(b.Kind())
+	if kindA != kindB {
+		d.add(ptr, DiffTypeChanged, a, b)
+		return nil
+	}
+
+	switch kindA {
+	case jsontext.KindBeginObject:
+		return d.diffObjects(ptr, a, b)
+	case jsontext.KindBeginArray:
+		return d.diffArrays(ptr, a, b)
+	case jsontext.KindString:
+		var sa, sb string
+		if err := json.Unmarshal(a, &sa); err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(b, &sb); err != nil {
+			return err
+		}
+
+		if sa != sb {
+			d.add(ptr, DiffChanged, a, b)
+		}
+	case jsontext.KindNumber:
+		ka, kb := bytes.TrimSpace(a), bytes.TrimSpace(b)
+		if d.ignoreNumberFormat {
+			var err error
+			if ka, err = appendNumberKey(nil, a); err != nil {
+				return err
+			}
+
+			if kb, err = appendNumberKey(nil, b); err != nil {
+				return err
+			}
+		}
+
+		if !bytes.Equal(ka, kb) {
+			d.add(ptr, DiffChanged, a, b)
+		}
+	default: // literals
+		if a.Kind() != b.Kind() {
+			d.add(ptr, DiffChanged, a, b)
+		}
+	}
+
+	return nil
+}
+
+func (d *differ) diffObjects(ptr jsontext.Pointer, a, b jsontext.Value) error {
+	membersA, err := readMembers(a)
+	if err != nil {
+		return err
+	}
+
+	membersB, err := readMembers(b)
+	if err != nil {
+		return err
+	}
+
+	indexB := make(map[string]int, len(membersB))
+	for j, m := range membersB {
+		indexB[m.name] = j
+	}
+
+	// the names both objects have, in the order of a and b
+	var commonA, commonB []string
+
+	for _, m := range membersA {
+		j, ok := indexB[m.name]
+		if !ok {
+			d.add(ptr.AppendToken(m.name), DiffRemoved, m.value, nil)
+			continue
+		}
+
+		commonA = append(commonA, m.name)
+		if err := d.diff(ptr.AppendToken(m.name), m.value, membersB[j].value); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range membersB {
+		if !slices.ContainsFunc(membersA, func(ma member) bool { return ma.name == m.name }) {
+			d.add(ptr.AppendToken(m.name), DiffAdded, nil, m.value)
+			continue
+		}
+
+		commonB = append(commonB, m.name)
+	}
+
+	if !d.ignoreKeyOrder && !slices.Equal(commonA, commonB) {
+		d.add(ptr, DiffChanged, a, b)
+	}
+
+	return nil
+}
+
+func (d *differ) diffArrays(ptr jsontext.Pointer, a, b jsontext.Value) error {
+	var elemsA, elemsB []jsontext.Value
+	if err := json.Unmarshal(a, &elemsA); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, &elemsB); err != nil {
+		return err
+	}
+
+	keysA, err := d.keys(ptr, elemsA)
+	if err != nil {
+		return err
+	}
+
+	keysB, err := d.keys(ptr, elemsB)
+	if err != nil {
+		return err
+	}
+
+	if d.isUnordered(ptr) {
+		d.diffUnorderedArrays(ptr, elemsA, elemsB, keysA, keysB)
+		return nil
+	}
+
+	edits := alignSequences(len(elemsA), len(elemsB), func(i, j int) bool { return keysA[i] == keysB[j] })
+
+	for _, e := range slices.Backward(edits) {
+		switch e.op {
+		case editSubstitute:
+			if err := d.diff(ptr.AppendToken(strconv.Itoa(e.i)), elemsA[e.i], elemsB[e.j]); err != nil {
+				return err
+			}
+		case editDelete:
+			d.add(ptr.AppendToken(strconv.Itoa(e.i)), DiffRemoved, elemsA[e.i], nil)
+		case editInsert:
+			d.add(ptr.AppendToken(strconv.Itoa(e.j)), DiffAdded, nil, elemsB[e.j])
+		}
+	}
+
+	return nil
+}
+
+// diffUnorderedArrays reports the elements of a that have no equal element in b as removed
+// and the elements of b that have no equal element in a as added.
+func (d *differ) diffUnorderedArrays(ptr jsontext.Pointer, elemsA, elemsB []jsontext.Value, keysA, keysB []string) {
+	// the indices of the elements of b that are not matched yet, by key
+	unmatched := make(map[string][]int, len(keysB))
+	for j, k := range keysB {
+		unmatched[k] = append(unmatched[k], j)
+	}
+
+	for i, k := range keysA {
+		if js := unmatched[k]; len(js) > 0 {
+			unmatched[k] = js[1:]
+			continue
+		}
+
+		d.add(ptr.AppendToken(strconv.Itoa(i)), DiffRemoved, elemsA[i], nil)
+	}
+
+	added := make([]bool, len(keysB))
+	for _, js := range unmatched {
+		for _, j := range js {
+			added[j] = true
+		}
+	}
+
+	for j, isAdded := range added {
+		if isAdded {
+			d.add(ptr.AppendToken(strconv.Itoa(j)), DiffAdded, nil, elemsB[j])
+		}
+	}
+}
+
+// matches reports whether the pointer matches the pattern, where a "*" token in the pattern matches any token.
+func (pattern pointerTokens) matches(ptr jsontext.Pointer) bool {
+	i := 0
+	for tok := range ptr.Tokens() {
+		if i == len(pattern) || (pattern[i] != "*" && pattern[i] != tok) {
+			return false
+		}
+
+		i++
+	}
+
+	return i == len(pattern)
+}
+
+// valueType returns the kind of a value, treating true and false as the same kind.
+func valueType(k jsontext.Kind) jsontext.Kind {
+	if k == 'f' {
+		return 't'
+	}
+
+	return k
+}
+
+// compactValue returns a compact copy of v.
+func compactValue(v jsontext.Value) jsontext.Value {
+	v = v.Clone()
+	_ = v.Compact()
+	return v
+}
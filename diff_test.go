@@ -0,0 +1,165 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name, a, b string
+		opts       []jsonutil.DiffOption
+		want       string
+	}{
+		{"equal", `{"a":[1,"x",true,null]}`, `{ "a": [1, "x", true, null] }`, nil, ""},
+		{
+			"object members",
+			`{"a":1,"b":{"c":2},"d":3}`,
+			`{"a":1,"b":{"c":"2"},"e":{"f":4}}`,
+			nil,
+			"@@ /b/c @@\n- 2\n+ \"2\"\n@@ /d @@\n- 3\n@@ /e @@\n+ {\"f\":4}\n",
+		},
+		{"changed literal", `[true]`, `[false]`, nil, "@@ /0 @@\n- true\n+ false\n"},
+		{
+			"array alignment",
+			`[1,2,3,4]`,
+			`[1,3,4,5]`,
+			nil,
+			"@@ /1 @@\n- 2\n@@ /3 @@\n+ 5\n",
+		},
+		{"key order", `{"a":1,"b":2}`, `{"b":2,"a":1}`, nil, "@@  @@\n- {\"a\":1,\"b\":2}\n+ {\"b\":2,\"a\":1}\n"},
+		{"ignore key order", `{"a":1,"b":2}`, `{"b":2,"a":1}`, []jsonutil.DiffOption{jsonutil.IgnoreKeyOrder()}, ""},
+		{"number format", `[1]`, `[1.0]`, nil, "@@ /0 @@\n- 1\n+ 1.0\n"},
+		{"ignore number format", `[1,2]`, `[1.0,2e0]`, []jsonutil.DiffOption{jsonutil.IgnoreNumberFormat()}, ""},
+		{
+			"ignore number format of large integers",
+			`[9007199254740993,1]`,
+			`[9007199254740992,1e0]`,
+			[]jsonutil.DiffOption{jsonutil.IgnoreNumberFormat()},
+			"@@ /0 @@\n- 9007199254740993\n+ 9007199254740992\n",
+		},
+		{
+			"ignore array order of large integers",
+			`[[9007199254740993],[1]]`,
+			`[[1.0],[9007199254740992]]`,
+			[]jsonutil.DiffOption{jsonutil.IgnoreNumberFormat(), jsonutil.IgnoreArrayOrder("")},
+			"@@ /0 @@\n- [9007199254740993]\n@@ /1 @@\n+ [9007199254740992]\n",
+		},
+		{
+			"ignore array order",
+			`{"users":[{"roles":["a","b","c"]}],"tags":[1,2]}`,
+			`{"users":[{"roles":["c","a","d"]}],"tags":[2,1]}`,
+			[]jsonutil.DiffOption{jsonutil.IgnoreArrayOrder("/users/*/roles")},
+			"@@ /users/0/roles/1 @@\n- \"b\"\n@@ /users/0/roles/2 @@\n+ \"d\"\n" +
+				"@@ /tags/0 @@\n- 1\n+ 2\n@@ /tags/1 @@\n- 2\n+ 1\n",
+		},
+		{
+			"array alignment with options",
+			`[{"a":1,"b":"\u0078"},{"a":2,"b":[1,2]},{"a":3}]`,
+			`[{"b":"x","a":1.0},{"a":4},{"b":[2,1],"a":2},{"a":3}]`,
+			[]jsonutil.DiffOption{
+				jsonutil.IgnoreKeyOrder(), jsonutil.IgnoreNumberFormat(), jsonutil.IgnoreArrayOrder("/*/b"),
+			},
+			"@@ /1 @@\n+ {\"a\":4}\n",
+		},
+		{
+			"unordered array with duplicates",
+			`[1,1,2,3]`,
+			`[3,1,4,4]`,
+			[]jsonutil.DiffOption{jsonutil.IgnoreArrayOrder("")},
+			"@@ /1 @@\n- 1\n@@ /2 @@\n- 2\n@@ /2 @@\n+ 4\n@@ /3 @@\n+ 4\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diffs, err := jsonutil.Diff(jsontext.Value(tc.a), jsontext.Value(tc.b), tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := diffs.String(); got != tc.want {
+				t.Fatalf("want:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("large arrays", func(t *testing.T) {
+		var a, b []string
+		for i := range 2000 {
+			a = append(a, fmt.Sprintf(`{"id":%d,"tags":["x","y"]}`, i))
+			b = append(b, fmt.Sprintf(`{"tags":["y","x"],"id":%d}`, (i+1)%2000))
+		}
+
+		diffs, err := jsonutil.Diff(
+			jsontext.Value("["+strings.Join(a, ",")+"]"), jsontext.Value("["+strings.Join(b, ",")+"]"),
+			jsonutil.IgnoreKeyOrder(), jsonutil.IgnoreArrayOrder("/*/tags"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// the first element moved to the end
+		if want := "@@ /0 @@\n- {\"id\":0,\"tags\":[\"x\",\"y\"]}\n@@ /1999 @@\n+ {\"tags\":[\"y\",\"x\"],\"id\":0}\n"; diffs.String() != want {
+			t.Fatalf("want:\n%s\ngot:\n%s", want, diffs)
+		}
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		diffs, err := jsonutil.Diff(
+			jsontext.Value(`{"a":1,"b":"x","c":[1]}`),
+			jsontext.Value(`{"a":2,"b":1,"d":null}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []struct {
+			ptr  string
+			kind jsonutil.DiffKind
+		}{
+			{"/a", jsonutil.DiffChanged},
+			{"/b", jsonutil.DiffTypeChanged},
+			{"/c", jsonutil.DiffRemoved},
+			{"/d", jsonutil.DiffAdded},
+		}
+
+		if len(diffs) != len(want) {
+			t.Fatalf("want %d differences, got: %v", len(want), diffs)
+		}
+
+		for i, w := range want {
+			if got := diffs[i]; string(got.Pointer) != w.ptr || got.Kind != w.kind {
+				t.Fatalf("difference %d: want %s %s, got %s %s", i, w.ptr, w.kind, got.Pointer, got.Kind)
+			}
+		}
+
+		if diffs[2].New != nil || diffs[3].Old != nil {
+			t.Fatalf("expected no new value for removed and no old value for added values")
+		}
+	})
+
+	t.Run("kind string", func(t *testing.T) {
+		if got := jsonutil.DiffTypeChanged.String(); got != "type-changed" {
+			t.Fatalf("want: type-changed, got: %s", got)
+		}
+
+		if got := jsonutil.DiffKind(0).String(); got != "DiffKind(0)" {
+			t.Fatalf("want: DiffKind(0), got: %s", got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := jsonutil.Diff(jsontext.Value(`{`), jsontext.Value(`{}`)); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if _, err := jsonutil.Diff(jsontext.Value(`{}`), jsontext.Value(`{`)); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if _, err := jsonutil.Diff(jsontext.Value(`{}`), jsontext.Value(`{}`), jsonutil.IgnoreArrayOrder("a")); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
@@ -1,8 +1,13 @@
 package jsonutil
 
 import (
+	"bytes"
 	"encoding/json/jsontext"
 	"encoding/json/v2"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,12 +17,17 @@ func DurationMarshalIntSeconds(enc *jsontext.Encoder, d time.Duration) error {
 }
 
 // DurationUnmarshalIntSeconds is a custom unmarshaler for time.Duration, unmarshaling them from integers and assuming they represent seconds.
+// Values that overflow time.Duration, i.e. beyond about 292 years, are rejected.
 func DurationUnmarshalIntSeconds(dec *jsontext.Decoder, d *time.Duration) error {
 	var seconds int64
 	if err := json.UnmarshalDecode(dec, &seconds); err != nil {
 		return err
 	}
 
+	if seconds > math.MaxInt64/int64(time.Second) || seconds < math.MinInt64/int64(time.Second) {
+		return fmt.Errorf("duration of %d seconds out of range", seconds)
+	}
+
 	*d = time.Duration(seconds) * time.Second
 
 	return nil
@@ -45,3 +55,223 @@ func DurationUnmarshalString(dec *jsontext.Decoder, d *time.Duration) error {
 	*d = parsed
 	return nil
 }
+
+// DurationMarshalISO8601 encodes a time.Duration as a JSON string in the ISO 8601 duration format
+// using hours, minutes and seconds, e.g. "PT1H30M" or "PT0.5S". Negative durations are prefixed with "-".
+func DurationMarshalISO8601(enc *jsontext.Encoder, d time.Duration) error {
+	return enc.WriteToken(jsontext.String(formatISO8601Duration(d)))
+}
+
+// DurationUnmarshalISO8601 decodes a JSON string in the ISO 8601 duration format into a time.Duration.
+// Weeks and days are 7*24 and 24 hours long. Years and months are rejected since their length varies.
+func DurationUnmarshalISO8601(dec *jsontext.Decoder, d *time.Duration) error {
+	var s string
+	if err := json.UnmarshalDecode(dec, &s); err != nil {
+		return err
+	}
+
+	parsed, err := parseISO8601Duration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+func formatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	b := []byte{}
+
+	// use uint64 so that the minimum duration can be negated
+	u := uint64(d)
+	if d < 0 {
+		b = append(b, '-')
+		u = -u
+	}
+
+	b = append(b, "PT"...)
+
+	if h := u / uint64(time.Hour); h > 0 {
+		b = append(strconv.AppendUint(b, h, 10), 'H')
+	}
+
+	if m := u / uint64(time.Minute) % 60; m > 0 {
+		b = append(strconv.AppendUint(b, m, 10), 'M')
+	}
+
+	if s, ns := u/uint64(time.Second)%60, u%uint64(time.Second); s > 0 || ns > 0 {
+		b = strconv.AppendUint(b, s, 10)
+		if ns > 0 {
+			frac := strconv.AppendUint(nil, ns+uint64(time.Second), 10)[1:] // zero padded to 9 digits
+			b = append(append(b, '.'), bytes.TrimRight(frac, "0")...)
+		}
+
+		b = append(b, 'S')
+	}
+
+	return string(b)
+}
+
+func parseISO8601Duration(s string) (time.Duration, error) {
+	orig := s
+
+	neg := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		neg, s = true, rest
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok || s == "" || s == "T" {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+	}
+
+	var (
+		total  uint64
+		inTime bool
+		units  = "WD" // the designators that may still follow, in order
+	)
+
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+			}
+
+			inTime, units, s = true, "HMS", s[1:]
+			if s == "" {
+				return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+			}
+
+			continue
+		}
+
+		i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+		}
+
+		num, designator := s[:i], s[i]
+		s = s[i+1:]
+
+		j := strings.IndexByte(units, designator)
+		if j < 0 {
+			if designator == 'Y' || designator == 'M' && !inTime {
+				return 0, fmt.Errorf("ISO 8601 duration %q: years and months are not supported", orig)
+			}
+
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+		}
+
+		units = units[j+1:]
+
+		var unit time.Duration
+		switch designator {
+		case 'W':
+			unit = 7 * 24 * time.Hour
+		case 'D':
+			unit = 24 * time.Hour
+		case 'H':
+			unit = time.Hour
+		case 'M':
+			unit = time.Minute
+		case 'S':
+			unit = time.Second
+		}
+
+		v, err := parseISO8601Number(num, uint64(unit))
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %w", orig, err)
+		}
+
+		if total += v; total < v {
+			return 0, fmt.Errorf("ISO 8601 duration %q out of range", orig)
+		}
+	}
+
+	if neg {
+		if total > 1<<63 {
+			return 0, fmt.Errorf("ISO 8601 duration %q out of range", orig)
+		}
+
+		return time.Duration(-total), nil
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("ISO 8601 duration %q out of range", orig)
+	}
+
+	return time.Duration(total), nil
+}
+
+// parseISO8601Number parses a decimal number with an optional fraction, separated by "." or ",",
+// and returns it multiplied by unit.
+func parseISO8601Number(s string, unit uint64) (uint64, error) {
+	intPart, frac, hasFrac := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	if intPart == "" || hasFrac && frac == "" {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+
+	n, err := strconv.ParseUint(intPart, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > math.MaxUint64/unit {
+		return 0, fmt.Errorf("number %q out of range", s)
+	}
+
+	v := n * unit
+
+	// add the fraction digit by digit, dropping what is below a nanosecond
+	for scale := unit; frac != "" && scale > 1; frac = frac[1:] {
+		digit := frac[0] - '0'
+		if digit > 9 {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+
+		scale /= 10
+		if v += uint64(digit) * scale; v < uint64(digit)*scale {
+			return 0, fmt.Errorf("number %q out of range", s)
+		}
+	}
+
+	if strings.ContainsFunc(frac, func(r rune) bool { return r < '0' || r > '9' }) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+
+	return v, nil
+}
+
+// SecondsDuration is a time.Duration that is marshaled as an integer representing seconds,
+// see DurationMarshalIntSeconds and DurationUnmarshalIntSeconds.
+type SecondsDuration time.Duration
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (d SecondsDuration) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return DurationMarshalIntSeconds(enc, time.Duration(d))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (d *SecondsDuration) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return DurationUnmarshalIntSeconds(dec, (*time.Duration)(d))
+}
+
+// StringDuration is a time.Duration that is marshaled as a string like "1h30m",
+// see DurationMarshalString and DurationUnmarshalString.
+type StringDuration time.Duration
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (d StringDuration) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return DurationMarshalString(enc, time.Duration(d))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (d *StringDuration) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return DurationUnmarshalString(dec, (*time.Duration)(d))
+}
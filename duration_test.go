@@ -4,12 +4,15 @@ import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"errors"
+	"math"
+	"math/rand/v2"
 	"reflect"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
 )
 
 func TestDuration(t *testing.T) {
@@ -110,3 +113,175 @@ func TestDuration(t *testing.T) {
 		})
 	}
 }
+
+var (
+	durationSecondsCodec = jsontest.Codec[time.Duration]{
+		Marshal:   jsonutil.DurationMarshalIntSeconds,
+		Unmarshal: jsonutil.DurationUnmarshalIntSeconds,
+	}
+	durationStringCodec = jsontest.Codec[time.Duration]{
+		Marshal:   jsonutil.DurationMarshalString,
+		Unmarshal: jsonutil.DurationUnmarshalString,
+	}
+)
+
+func TestDurationRoundTrip(t *testing.T) {
+	t.Run("seconds", func(t *testing.T) {
+		jsontest.CheckRoundTrip(t, durationSecondsCodec, func(r *rand.Rand) time.Duration {
+			return time.Duration(r.Int64N(1<<33)-1<<32) * time.Second
+		}, 1000)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		jsontest.CheckRoundTrip(t, durationStringCodec, func(r *rand.Rand) time.Duration {
+			return time.Duration(r.Int64())
+		}, 1000)
+	})
+
+	t.Run("seconds out of range", func(t *testing.T) {
+		var d time.Duration
+		if err := json.Unmarshal([]byte(`10000000000`), &d, durationSecondsCodec.Options()); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func FuzzDurationSeconds(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, durationSecondsCodec, `0`, `1`, `-1`, `3600`, `9223372036`, `-9223372036`, `null`)
+}
+
+func FuzzDurationString(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, durationStringCodec,
+		`"0s"`, `"1h30m"`, `"-1.5us"`, `"2562047h47m16.854775807s"`, `"-2562047h47m16.854775808s"`, `"1"`, `null`,
+	)
+}
+
+var durationISO8601Codec = jsontest.Codec[time.Duration]{
+	Marshal:   jsonutil.DurationMarshalISO8601,
+	Unmarshal: jsonutil.DurationUnmarshalISO8601,
+}
+
+func TestDurationISO8601(t *testing.T) {
+	jsonOpts := durationISO8601Codec.Options()
+
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, `"PT0S"`},
+		{90 * time.Minute, `"PT1H30M"`},
+		{36 * time.Hour, `"PT36H"`},
+		{time.Second / 2, `"PT0.5S"`},
+		{time.Nanosecond, `"PT0.000000001S"`},
+		{-(time.Hour + time.Second + time.Millisecond), `"-PT1H1.001S"`},
+		{math.MinInt64, `"-PT2562047H47M16.854775808S"`},
+	} {
+		t.Run(tc.want, func(t *testing.T) {
+			b, err := json.Marshal(tc.d, jsonOpts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, b)
+			}
+
+			var got time.Duration
+			if err := json.Unmarshal(b, &got, jsonOpts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.d {
+				t.Fatalf("want: %s, got: %s", tc.d, got)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		in   string
+		want time.Duration
+	}{
+		{`"P1D"`, 24 * time.Hour},
+		{`"P1W"`, 7 * 24 * time.Hour},
+		{`"P1DT12H"`, 36 * time.Hour},
+		{`"PT1.5M"`, 90 * time.Second},
+		{`"PT0,25S"`, time.Second / 4},
+		{`"+PT1S"`, time.Second},
+		{`"PT2562047H47M16.854775807S"`, math.MaxInt64},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var got time.Duration
+			if err := json.Unmarshal([]byte(tc.in), &got, jsonOpts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+
+	for _, in := range []string{
+		`""`, `"P"`, `"PT"`, `"1H"`, `"P1H"`, `"PT1D"`, `"P1Y"`, `"P1M"`, `"PT1S1M"`, `"P1DT"`, `"PTT1H"`,
+		`"PT.5S"`, `"PT1.S"`, `"PT1.5xS"`, `"PT99999999999999999999S"`, `"PT2562047H47M16.854775808S"`,
+		`"-PT2562047H47M16.854775809S"`, `3600`,
+	} {
+		t.Run(in, func(t *testing.T) {
+			var got time.Duration
+			if err := json.Unmarshal([]byte(in), &got, jsonOpts); err == nil {
+				t.Fatalf("expected error, got: %s", got)
+			}
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		jsontest.CheckRoundTrip(t, durationISO8601Codec, func(r *rand.Rand) time.Duration {
+			return time.Duration(r.Int64())
+		}, 1000)
+	})
+}
+
+func FuzzDurationISO8601(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, durationISO8601Codec,
+		`"PT0S"`, `"P1DT12H"`, `"-PT1.5S"`, `"PT0,25S"`, `"P2W"`, `"-PT2562047H47M16.854775808S"`, `"P1Y"`,
+	)
+}
+
+func TestDurationTypes(t *testing.T) {
+	type durations struct {
+		Seconds jsonutil.SecondsDuration  `json:"seconds"`
+		String  jsonutil.StringDuration   `json:"string"`
+		Pointer *jsonutil.SecondsDuration `json:"pointer"`
+		Map     map[string]jsonutil.StringDuration
+	}
+
+	v := durations{
+		Seconds: jsonutil.SecondsDuration(90 * time.Second),
+		String:  jsonutil.StringDuration(90 * time.Minute),
+		Map:     map[string]jsonutil.StringDuration{"a": jsonutil.StringDuration(time.Millisecond)},
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"seconds":90,"string":"1h30m0s","pointer":null,"Map":{"a":"1ms"}}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out durations
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(out, v) {
+		t.Fatalf("want: %+v, got: %+v", v, out)
+	}
+
+	for _, in := range []string{`{"seconds":"90"}`, `{"string":90}`, `{"string":"90"}`} {
+		if err := json.Unmarshal([]byte(in), &out); err == nil {
+			t.Fatalf("expected error for %s", in)
+		}
+	}
+}
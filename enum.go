@@ -0,0 +1,164 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownEnumValue is returned when marshaling or unmarshaling a value that is not part of an enum.
+var ErrUnknownEnumValue = errors.New("unknown enum value")
+
+// EnumCodec marshals the values of an enum, e.g. of an integer type, as their names and unmarshals them
+// from their names, rejecting unknown values, e.g.
+//
+//	colors := jsonutil.NewEnumCodec(map[Color]string{Red: "red", Green: "green"}).
+//		CaseInsensitive().
+//		WithAliases(map[string]Color{"crimson": Red})
+//
+//	err := json.Unmarshal(b, &c, colors.Options())
+//
+// Configure the codec before use; an EnumCodec is not safe for concurrent configuration.
+type EnumCodec[T comparable] struct {
+	names    map[T]string
+	values   map[string]T // names and aliases
+	folded   map[string]T // lowercase names and aliases if case-insensitive
+	fallback *T
+}
+
+// NewEnumCodec returns an EnumCodec for the values and names of the enum.
+// It panics if two values have the same name.
+func NewEnumCodec[T comparable](names map[T]string) *EnumCodec[T] {
+	c := &EnumCodec[T]{names: maps.Clone(names), values: make(map[string]T, len(names))}
+	for v, name := range names {
+		if other, ok := c.values[name]; ok {
+			panic(fmt.Sprintf("jsonutil: enum values %v and %v have the same name %q", v, other, name))
+		}
+
+		c.values[name] = v
+	}
+
+	return c
+}
+
+// CaseInsensitive makes the codec match names and aliases regardless of case when unmarshaling.
+// It panics if two of them only differ in case but are not of the same value.
+func (c *EnumCodec[T]) CaseInsensitive() *EnumCodec[T] {
+	c.folded = map[string]T{}
+	c.fold()
+
+	return c
+}
+
+// WithAliases adds alternative names, e.g. legacy ones, that are accepted when unmarshaling.
+// Values are always marshaled as their names. It panics if an alias is already a name or alias of another value.
+func (c *EnumCodec[T]) WithAliases(aliases map[string]T) *EnumCodec[T] {
+	for alias, v := range aliases {
+		if other, ok := c.values[alias]; ok && other != v {
+			panic(fmt.Sprintf("jsonutil: enum alias %q of %v is already used for %v", alias, v, other))
+		}
+
+		c.values[alias] = v
+	}
+
+	c.fold()
+
+	return c
+}
+
+// WithFallback sets the value that unknown names unmarshal as instead of returning an error.
+func (c *EnumCodec[T]) WithFallback(v T) *EnumCodec[T] {
+	c.fallback = &v
+	return c
+}
+
+// Options returns JSON options that use the codec for values of type T.
+// To combine it with other marshalers, join Marshal and Unmarshal with them instead.
+func (c *EnumCodec[T]) Options() json.Options {
+	return json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(c.Marshal)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(c.Unmarshal)),
+	)
+}
+
+// Marshal is a custom marshaler for the enum, marshaling values as their names.
+func (c *EnumCodec[T]) Marshal(enc *jsontext.Encoder, v T) error {
+	name, ok := c.names[v]
+	if !ok {
+		return fmt.Errorf("%w %v, want one of %s", ErrUnknownEnumValue, v, c.allowed())
+	}
+
+	return enc.WriteToken(jsontext.String(name))
+}
+
+// Unmarshal is a custom unmarshaler for the enum, unmarshaling values from their names or aliases.
+// Nulls unmarshal as the zero value.
+func (c *EnumCodec[T]) Unmarshal(dec *jsontext.Decoder, v *T) error {
+	tkn, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+
+	switch tkn.Kind() {
+	case jsontext.KindString:
+	case jsontext.KindNull:
+		*v = *new(T)
+		return nil
+	default:
+		return fmt.Errorf("cannot unmarshal JSON %s into an enum, want one of %s", tkn.Kind(), c.allowed())
+	}
+
+	name := tkn.String()
+	if val, ok := c.lookup(name); ok {
+		*v = val
+		return nil
+	}
+
+	if c.fallback != nil {
+		*v = *c.fallback
+		return nil
+	}
+
+	return fmt.Errorf("%w %q, want one of %s", ErrUnknownEnumValue, name, c.allowed())
+}
+
+func (c *EnumCodec[T]) lookup(name string) (T, bool) {
+	if v, ok := c.values[name]; ok || c.folded == nil {
+		return v, ok
+	}
+
+	v, ok := c.folded[strings.ToLower(name)]
+	return v, ok
+}
+
+// fold adds the lowercase names and aliases to the index if the codec is case-insensitive.
+// It panics if two of them of different values only differ in case.
+func (c *EnumCodec[T]) fold() {
+	if c.folded == nil {
+		return
+	}
+
+	for name, v := range c.values {
+		folded := strings.ToLower(name)
+		if other, ok := c.folded[folded]; ok && other != v {
+			panic(fmt.Sprintf("jsonutil: enum name %q of %v only differs in case from one of %v", name, v, other))
+		}
+
+		c.folded[folded] = v
+	}
+}
+
+// allowed returns the sorted names of the enum as a list.
+func (c *EnumCodec[T]) allowed() string {
+	names := slices.Sorted(maps.Values(c.names))
+	for i, n := range names {
+		names[i] = strconv.Quote(n)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,190 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testColor int
+
+const (
+	testColorUnknown testColor = iota
+	testColorRed
+	testColorGreen
+	testColorBlue
+)
+
+type testStatus string
+
+var testColorNames = map[testColor]string{testColorRed: "red", testColorGreen: "green", testColorBlue: "blue"}
+
+func TestEnumCodec(t *testing.T) {
+	type testPaint struct {
+		Color   testColor   `json:"color"`
+		Palette []testColor `json:"palette"`
+	}
+
+	opts := jsonutil.NewEnumCodec(testColorNames).Options()
+
+	t.Run("round trip", func(t *testing.T) {
+		v := testPaint{Color: testColorGreen, Palette: []testColor{testColorBlue, testColorRed}}
+
+		b, err := json.Marshal(v, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"color":"green","palette":["blue","red"]}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var out testPaint
+		if err := json.Unmarshal(b, &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.Color != v.Color || len(out.Palette) != 2 || out.Palette[0] != testColorBlue {
+			t.Fatalf("want: %+v, got: %+v", v, out)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		out := testPaint{Color: testColorRed}
+		if err := json.Unmarshal([]byte(`{"color":null}`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.Color != testColorUnknown {
+			t.Fatalf("want: zero, got: %v", out.Color)
+		}
+	})
+
+	t.Run("unknown value", func(t *testing.T) {
+		_, err := json.Marshal(testPaint{}, opts)
+		if !errors.Is(err, jsonutil.ErrUnknownEnumValue) {
+			t.Fatalf("expected ErrUnknownEnumValue, got: %v", err)
+		}
+
+		if want := `want one of "blue", "green", "red"`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to contain %q, got: %v", want, err)
+		}
+	})
+
+	for _, in := range []string{`{"color":"Red"}`, `{"color":"purple"}`, `{"color":""}`} {
+		t.Run("unknown name "+in, func(t *testing.T) {
+			var out testPaint
+			err := json.Unmarshal([]byte(in), &out, opts)
+			if !errors.Is(err, jsonutil.ErrUnknownEnumValue) {
+				t.Fatalf("expected ErrUnknownEnumValue, got: %v", err)
+			}
+
+			if want := `want one of "blue", "green", "red"`; !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected error to contain %q, got: %v", want, err)
+			}
+		})
+	}
+
+	t.Run("not a string", func(t *testing.T) {
+		var out testPaint
+		if err := json.Unmarshal([]byte(`{"color":1}`), &out, opts); err == nil {
+			t.Fatalf("expected error")
+		} else if !strings.Contains(err.Error(), "want one of") {
+			t.Fatalf("expected error to list allowed values, got: %v", err)
+		}
+	})
+
+	t.Run("case insensitive with aliases", func(t *testing.T) {
+		opts := jsonutil.NewEnumCodec(testColorNames).
+			CaseInsensitive().
+			WithAliases(map[string]testColor{"Crimson": testColorRed, "GREEN": testColorGreen}).
+			Options()
+
+		for in, want := range map[string]testColor{
+			`"RED"`: testColorRed, `"crimson"`: testColorRed, `"CRIMSON"`: testColorRed,
+			`"green"`: testColorGreen, `"Blue"`: testColorBlue,
+		} {
+			var out testColor
+			if err := json.Unmarshal([]byte(in), &out, opts); err != nil {
+				t.Fatalf("%s: unexpected error: %v", in, err)
+			}
+
+			if out != want {
+				t.Fatalf("%s: want: %v, got: %v", in, want, out)
+			}
+		}
+
+		// aliases are not used when marshaling
+		if b, err := json.Marshal(testColorRed, opts); err != nil || string(b) != `"red"` {
+			t.Fatalf(`want: "red", got: %s (%v)`, b, err)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		opts := jsonutil.NewEnumCodec(testColorNames).WithFallback(testColorUnknown).Options()
+
+		out := testColorRed
+		if err := json.Unmarshal([]byte(`"purple"`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != testColorUnknown {
+			t.Fatalf("want: zero, got: %v", out)
+		}
+
+		// unknown values are still rejected when marshaling
+		if _, err := json.Marshal(out, opts); !errors.Is(err, jsonutil.ErrUnknownEnumValue) {
+			t.Fatalf("expected ErrUnknownEnumValue, got: %v", err)
+		}
+	})
+
+	t.Run("string-backed", func(t *testing.T) {
+		opts := jsonutil.NewEnumCodec(map[testStatus]string{"active": "active", "inactive": "inactive"}).
+			WithAliases(map[string]testStatus{"enabled": "active"}).
+			Options()
+
+		var out testStatus
+		if err := json.Unmarshal([]byte(`"enabled"`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != "active" {
+			t.Fatalf("want: active, got: %s", out)
+		}
+
+		if _, err := json.Marshal(testStatus("deleted"), opts); !errors.Is(err, jsonutil.ErrUnknownEnumValue) {
+			t.Fatalf("expected ErrUnknownEnumValue, got: %v", err)
+		}
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		for name, configure := range map[string]func(){
+			"duplicate name": func() {
+				jsonutil.NewEnumCodec(map[testColor]string{testColorRed: "red", testColorBlue: "red"})
+			},
+			"alias of another value": func() {
+				jsonutil.NewEnumCodec(testColorNames).WithAliases(map[string]testColor{"red": testColorBlue})
+			},
+			"ambiguous case": func() {
+				jsonutil.NewEnumCodec(map[testColor]string{testColorRed: "a", testColorBlue: "A"}).CaseInsensitive()
+			},
+			"ambiguous alias": func() {
+				jsonutil.NewEnumCodec(testColorNames).CaseInsensitive().
+					WithAliases(map[string]testColor{"RED": testColorBlue})
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("expected panic")
+					}
+				}()
+
+				configure()
+			})
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/civil"
+)
+
+// FieldFormats returns JSON options that select the representation of struct fields
+// with a `jsonutil` tag, so that fields of the same type can use different formats, e.g.
+//
+//	type Event struct {
+//		Start    time.Time     `json:"start" jsonutil:"unix"`
+//		Created  time.Time     `json:"created" jsonutil:"rfc3339"`
+//		Duration time.Duration `json:"duration" jsonutil:"iso8601"`
+//	}
+//
+// The supported formats, for fields of the type or a pointer to it, are:
+//   - time.Time: "unix" (see UnixTime), "unixmilli" (see UnixMilliTime) and "rfc3339"
+//   - civil.Date: "unix" (see DateMarshalIntUnix) and "string" (see ISODate)
+//   - time.Duration: "seconds" (see SecondsDuration), "string" (see StringDuration)
+//     and "iso8601" (see DurationMarshalISO8601)
+//   - url.URL: "string" (see JSONURL)
+//   - http.Header: "single" (see SingleValueHeader)
+//
+// A tagged field takes precedence over the type-specific marshalers of other options.
+// Tagged fields in embedded structs are supported as well, as long as the embedded struct type is exported.
+// To use it together with Options, set Config.FieldFormats instead.
+func FieldFormats() json.Options {
+	return json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(marshalFieldFormats)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(unmarshalFieldFormats)),
+	)
+}
+
+// marshalFieldFormats marshals structs with tagged fields as a shadow struct
+// whose tagged fields are of a type that implements the format.
+func marshalFieldFormats(enc *jsontext.Encoder, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.ErrUnsupported
+	}
+
+	p, err := fieldFormatPlanFor(rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	if p == nil {
+		return errors.ErrUnsupported
+	}
+
+	shadow := reflect.New(p.shadow)
+	p.toShadow(shadow.Elem(), rv.Elem())
+
+	return json.MarshalEncode(enc, shadow.Interface())
+}
+
+func unmarshalFieldFormats(dec *jsontext.Decoder, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.ErrUnsupported
+	}
+
+	p, err := fieldFormatPlanFor(rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	if p == nil {
+		return errors.ErrUnsupported
+	}
+
+	// start with the current value, since unmarshaling merges into existing values
+	shadow := reflect.New(p.shadow)
+	p.toShadow(shadow.Elem(), rv.Elem())
+
+	if err := json.UnmarshalDecode(dec, shadow.Interface()); err != nil {
+		return err
+	}
+
+	p.fromShadow(rv.Elem(), shadow.Elem())
+	return nil
+}
+
+// fieldFormatPlan describes the shadow struct of a struct type with tagged fields.
+type fieldFormatPlan struct {
+	shadow reflect.Type
+	fields []shadowField // one per field of the shadow struct
+}
+
+// shadowField is a field of a shadow struct.
+type shadowField struct {
+	index int              // index of the field in the original struct
+	embed *fieldFormatPlan // the plan of an embedded struct with tagged fields
+}
+
+type fieldFormatPlanResult struct {
+	plan *fieldFormatPlan
+	err  error
+}
+
+var fieldFormatPlans sync.Map // reflect.Type -> fieldFormatPlanResult
+
+// fieldFormatPlanFor returns the plan for a struct type or nil if it has no tagged fields.
+func fieldFormatPlanFor(t reflect.Type) (*fieldFormatPlan, error) {
+	if r, ok := fieldFormatPlans.Load(t); ok {
+		res := r.(fieldFormatPlanResult)
+		return res.plan, res.err
+	}
+
+	p, err := newFieldFormatPlan(t, map[reflect.Type]bool{})
+	fieldFormatPlans.Store(t, fieldFormatPlanResult{p, err})
+
+	return p, err
+}
+
+func newFieldFormatPlan(t reflect.Type, seen map[reflect.Type]bool) (*fieldFormatPlan, error) {
+	if seen[t] {
+		return nil, nil // recursive embedding
+	}
+
+	seen[t] = true
+	defer delete(seen, t)
+
+	var (
+		fields       []reflect.StructField
+		p            = &fieldFormatPlan{}
+		hasFormatted bool
+	)
+
+	for f := range t.Fields() {
+		sf := shadowField{index: f.Index[0]}
+		field := reflect.StructField{Name: f.Name, Type: f.Type}
+
+		// keep only the json tag, so that the shadow struct has no tagged fields itself
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			field.Tag = reflect.StructTag("json:" + strconv.Quote(tag))
+		}
+
+		if format, ok := f.Tag.Lookup("jsonutil"); ok {
+			if !f.IsExported() {
+				return nil, fmt.Errorf("jsonutil: unexported field %s of %s has a jsonutil tag", f.Name, t)
+			}
+
+			wt, err := formatType(f.Type, format)
+			if err != nil {
+				return nil, fmt.Errorf("jsonutil: field %s of %s: %w", f.Name, t, err)
+			}
+
+			field.Type, hasFormatted = wt, true
+		} else if isEmbeddedStruct(f) {
+			et := indirectType(f.Type)
+
+			sub, err := newFieldFormatPlan(et, seen)
+			if err != nil {
+				return nil, err
+			}
+
+			if sub != nil {
+				if !f.IsExported() {
+					return nil, fmt.Errorf("jsonutil: unexported embedded field %s of %s has fields with a jsonutil tag", f.Name, t)
+				}
+
+				sf.embed, hasFormatted = sub, true
+				field.Type = sub.shadow
+				if f.Type.Kind() == reflect.Pointer {
+					field.Type = reflect.PointerTo(sub.shadow)
+				}
+			}
+
+			// name the field so that the shadow struct does not embed it in the Go sense
+			field.Name, field.Tag = "Embedded"+strconv.Itoa(sf.index), `json:",embed"`
+		} else if !f.IsExported() {
+			continue
+		}
+
+		fields = append(fields, field)
+		p.fields = append(p.fields, sf)
+	}
+
+	if !hasFormatted {
+		return nil, nil
+	}
+
+	p.shadow = reflect.StructOf(fields)
+	return p, nil
+}
+
+// isEmbeddedStruct reports whether the field is a struct, or pointer to one, that is embedded in JSON.
+func isEmbeddedStruct(f reflect.StructField) bool {
+	if indirectType(f.Type).Kind() != reflect.Struct {
+		return false
+	}
+
+	name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" && opts == "" {
+		return false
+	}
+
+	return slices.ContainsFunc(strings.Split(opts, ","), func(opt string) bool {
+		return opt == "embed" || opt == "inline"
+	}) || f.Anonymous && name == ""
+}
+
+// toShadow copies the fields of the addressable struct v to the shadow struct.
+func (p *fieldFormatPlan) toShadow(shadow, v reflect.Value) {
+	for i, sf := range p.fields {
+		src, dst := v.Field(sf.index), shadow.Field(i)
+
+		switch {
+		case sf.embed == nil:
+			dst.Set(src.Convert(dst.Type()))
+		case src.Kind() == reflect.Pointer:
+			if !src.IsNil() {
+				dst.Set(reflect.New(sf.embed.shadow))
+				sf.embed.toShadow(dst.Elem(), src.Elem())
+			}
+		default:
+			sf.embed.toShadow(dst, src)
+		}
+	}
+}
+
+// fromShadow copies the fields of the shadow struct to v.
+func (p *fieldFormatPlan) fromShadow(v, shadow reflect.Value) {
+	for i, sf := range p.fields {
+		src, dst := shadow.Field(i), v.Field(sf.index)
+
+		switch {
+		case sf.embed == nil:
+			dst.Set(src.Convert(dst.Type()))
+		case src.Kind() == reflect.Pointer:
+			if src.IsNil() {
+				dst.SetZero()
+				continue
+			}
+
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+
+			sf.embed.fromShadow(dst.Elem(), src.Elem())
+		default:
+			sf.embed.fromShadow(dst, src)
+		}
+	}
+}
+
+// fieldFormatTypes maps the supported field types and formats to the types implementing the format.
+var fieldFormatTypes = map[reflect.Type]map[string]reflect.Type{
+	reflect.TypeFor[time.Time](): {
+		"unix":      reflect.TypeFor[UnixTime](),
+		"unixmilli": reflect.TypeFor[UnixMilliTime](),
+		"rfc3339":   reflect.TypeFor[rfc3339Time](),
+	},
+	reflect.TypeFor[civil.Date](): {
+		"unix":   reflect.TypeFor[unixDate](),
+		"string": reflect.TypeFor[ISODate](),
+	},
+	reflect.TypeFor[time.Duration](): {
+		"seconds": reflect.TypeFor[SecondsDuration](),
+		"string":  reflect.TypeFor[StringDuration](),
+		"iso8601": reflect.TypeFor[iso8601Duration](),
+	},
+	reflect.TypeFor[url.URL](): {
+		"string": reflect.TypeFor[JSONURL](),
+	},
+	reflect.TypeFor[http.Header](): {
+		"single": reflect.TypeFor[SingleValueHeader](),
+	},
+}
+
+// formatType returns the type that implements the format for values of type t.
+func formatType(t reflect.Type, format string) (reflect.Type, error) {
+	base := t
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+
+	formats, ok := fieldFormatTypes[base]
+	if !ok {
+		return nil, fmt.Errorf("no formats for type %s", t)
+	}
+
+	ft, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q for type %s", format, t)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return reflect.PointerTo(ft), nil
+	}
+
+	return ft, nil
+}
+
+type rfc3339Time time.Time
+
+func (t rfc3339Time) IsZero() bool { return time.Time(t).IsZero() }
+
+func (t rfc3339Time) MarshalJSONTo(enc *jsontext.Encoder) error {
+	b, err := time.Time(t).MarshalText()
+	if err != nil {
+		return err
+	}
+
+	return enc.WriteToken(jsontext.String(string(b)))
+}
+
+func (t *rfc3339Time) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var s string
+	if err := json.UnmarshalDecode(dec, &s); err != nil {
+		return err
+	}
+
+	return (*time.Time)(t).UnmarshalText([]byte(s))
+}
+
+type unixDate civil.Date
+
+func (d unixDate) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return DateMarshalIntUnix(enc, civil.Date(d))
+}
+
+func (d *unixDate) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return DateUnmarshalIntUnix(dec, (*civil.Date)(d))
+}
+
+type iso8601Duration time.Duration
+
+func (d iso8601Duration) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return DurationMarshalISO8601(enc, time.Duration(d))
+}
+
+func (d *iso8601Duration) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return DurationUnmarshalISO8601(dec, (*time.Duration)(d))
+}
@@ -0,0 +1,182 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testFieldFormatBase struct {
+	Created time.Time `json:"created" jsonutil:"unix"`
+}
+
+type testFieldFormat struct {
+	Base testFieldFormatBase `json:",inline"`
+
+	Start       time.Time      `json:"start" jsonutil:"unix"`
+	End         *time.Time     `json:"end,omitzero" jsonutil:"rfc3339"`
+	Default     time.Time      `json:"default"`
+	Day         civil.Date     `json:"day" jsonutil:"unix"`
+	DayString   civil.Date     `json:"dayString" jsonutil:"string"`
+	Timeout     time.Duration  `json:"timeout" jsonutil:"seconds"`
+	Interval    time.Duration  `json:"interval" jsonutil:"string"`
+	Period      *time.Duration `json:"period" jsonutil:"iso8601"`
+	Link        url.URL        `json:"link" jsonutil:"string"`
+	Header      http.Header    `json:"header" jsonutil:"single"`
+	Nested      []testFieldFormatBase
+	unexported  int
+	Unformatted int `json:"unformatted"`
+}
+
+func TestFieldFormats(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	period := 90 * time.Minute
+
+	v := testFieldFormat{
+		Base:        testFieldFormatBase{Created: ts},
+		Start:       ts,
+		End:         &ts,
+		Default:     ts,
+		Day:         civil.Date{Year: 2024, Month: time.January, Day: 2},
+		DayString:   civil.Date{Year: 2024, Month: time.January, Day: 2},
+		Timeout:     30 * time.Second,
+		Interval:    time.Minute,
+		Period:      &period,
+		Link:        url.URL{Scheme: "https", Host: "example.com"},
+		Header:      http.Header{"Accept": {"text/plain"}},
+		Nested:      []testFieldFormatBase{{Created: ts}},
+		unexported:  1,
+		Unformatted: 2,
+	}
+
+	const want = `{"created":1704164645,"start":1704164645,"end":"2024-01-02T03:04:05Z",` +
+		`"default":"2024-01-02T03:04:05Z","day":1704153600,"dayString":"2024-01-02","timeout":30,` +
+		`"interval":"1m0s","period":"PT1H30M","link":"https://example.com","header":{"Accept":"text/plain"},` +
+		`"Nested":[{"created":1704164645}],"unformatted":2}`
+
+	for _, tc := range []struct {
+		name string
+		opts json.Options
+	}{
+		{"FieldFormats", jsonutil.FieldFormats()},
+		{"Options", jsonutil.Options(jsonutil.Config{FieldFormats: true})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(v, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != want {
+				t.Fatalf("want: %s, got: %s", want, b)
+			}
+
+			out := testFieldFormat{unexported: 3}
+			if err := json.Unmarshal(b, &out, tc.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !out.Base.Created.Equal(ts) || !out.Start.Equal(ts) || !out.End.Equal(ts) || !out.Default.Equal(ts) ||
+				out.Day != v.Day || out.DayString != v.DayString || out.Timeout != v.Timeout ||
+				out.Interval != v.Interval || *out.Period != period || out.Link != v.Link ||
+				out.Header.Get("Accept") != "text/plain" || !out.Nested[0].Created.Equal(ts) ||
+				out.unexported != 3 || out.Unformatted != 2 {
+				t.Fatalf("want: %+v, got: %+v", v, out)
+			}
+		})
+	}
+
+	t.Run("precedence over type-specific marshalers", func(t *testing.T) {
+		type mixed struct {
+			A time.Time `json:"a" jsonutil:"rfc3339"`
+			B time.Time `json:"b"`
+			C time.Time `json:"c" jsonutil:"unixmilli"`
+		}
+
+		opts := jsonutil.Options(jsonutil.Config{Time: jsonutil.TimeUnixSeconds, FieldFormats: true})
+
+		b, err := json.Marshal(mixed{A: ts, B: ts, C: ts}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"a":"2024-01-02T03:04:05Z","b":1704164645,"c":1704164645000}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("embedded pointer", func(t *testing.T) {
+		type withPointer struct {
+			Base *testFieldFormatBase `json:",inline"`
+			Name string               `json:"name"`
+		}
+
+		b, err := json.Marshal(withPointer{Name: "a"}, jsonutil.FieldFormats())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"name":"a"}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var out withPointer
+		if err := json.Unmarshal([]byte(`{"created":1704164645}`), &out, jsonutil.FieldFormats()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.Base == nil || !out.Base.Created.Equal(ts) {
+			t.Fatalf("want: %s, got: %+v", ts, out.Base)
+		}
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		b, err := json.Marshal(struct {
+			T time.Time `json:"t"`
+		}{ts}, jsonutil.FieldFormats())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"t":"2024-01-02T03:04:05Z"}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		v    any
+		msg  string
+	}{
+		{"unknown format", &struct {
+			T time.Time `jsonutil:"unixmicro"`
+		}{}, `unknown format "unixmicro"`},
+		{"unsupported type", &struct {
+			I int `jsonutil:"unix"`
+		}{}, "no formats for type int"},
+		{"unexported field", &struct {
+			t time.Time `jsonutil:"unix"`
+		}{}, "unexported field t"},
+		{"unexported embedded field", &struct {
+			testFieldFormatBase
+		}{}, "unexported embedded field testFieldFormatBase"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := json.Marshal(tc.v, jsonutil.FieldFormats()); err == nil {
+				t.Fatalf("expected error")
+			} else if !strings.Contains(err.Error(), tc.msg) {
+				t.Fatalf("expected error to contain %q, got: %v", tc.msg, err)
+			}
+
+			if err := json.Unmarshal([]byte(`{}`), tc.v, jsonutil.FieldFormats()); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FileError describes an error that occurred when reading or writing a json file.
+// It wraps the underlying error, so errors.As still finds a jsontext.SyntacticError or json.SemanticError.
+type FileError struct {
+	// Path is the name of the file.
+	Path string
+	// Line and Column are the 1-based position of the error in the file, where the column counts characters.
+	// They are zero if the position is unknown, e.g. when writing a file.
+	Line, Column int
+	// JSONPointer points to the JSON value in which the error occurred.
+	JSONPointer jsontext.Pointer
+	// Excerpt is the line of the file where the error occurred,
+	// followed by a line with a caret marking the column.
+	// Long lines, e.g. of minified files, are clipped to the characters around the column.
+	Excerpt string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FileError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %v", e.Path, e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FileError) Unwrap() error { return e.Err }
+
+// newFileError wraps a JSON error with the file name and, if data is given, the position of the error in it.
+// Errors that are not JSON errors are returned as is.
+func newFileError(name string, data []byte, err error) error {
+	fileErr := &FileError{Path: name, Err: err}
+
+	offset := int64(-1)
+	if errSyn := (*jsontext.SyntacticError)(nil); errors.As(err, &errSyn) {
+		fileErr.JSONPointer, offset = errSyn.JSONPointer, errSyn.ByteOffset
+	} else if errSem := (*json.SemanticError)(nil); errors.As(err, &errSem) {
+		fileErr.JSONPointer, offset = errSem.JSONPointer, errSem.ByteOffset
+	} else {
+		return err
+	}
+
+	if data == nil || offset < 0 || offset > int64(len(data)) {
+		return fileErr
+	}
+
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := len(data)
+	if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+		end = int(offset) + i
+	}
+
+	prefix := []rune(string(data[start:offset]))
+	suffix := []rune(strings.TrimSuffix(string(data[offset:end]), "\r"))
+
+	fileErr.Line = bytes.Count(data[:offset], []byte("\n")) + 1
+	fileErr.Column = len(prefix) + 1
+
+	if len(prefix) > excerptRadius {
+		prefix = append([]rune(excerptEllipsis), prefix[len(prefix)-excerptRadius:]...)
+	}
+
+	if len(suffix) > excerptRadius {
+		suffix = append(suffix[:excerptRadius:excerptRadius], []rune(excerptEllipsis)...)
+	}
+
+	fileErr.Excerpt = string(prefix) + string(suffix) + "\n" + caretIndent(prefix) + "^"
+
+	return fileErr
+}
+
+// excerptRadius is the number of characters an excerpt shows before and after the column of an error.
+const excerptRadius = 40
+
+// excerptEllipsis marks where an excerpt was clipped.
+const excerptEllipsis = "..."
+
+// caretIndent returns whitespace that aligns a caret under the character following prefix.
+// Tabs are kept so the alignment is independent of the tab width.
+func caretIndent(prefix []rune) string {
+	var sb strings.Builder
+	for _, r := range prefix {
+		if r == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestFileError(t *testing.T) {
+	type config struct {
+		Name     string        `json:"name"`
+		Duration time.Duration `json:"duration"`
+	}
+
+	jsonOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.DurationMarshalIntSeconds)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds)),
+	)
+
+	dir := t.TempDir()
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+
+		name := filepath.Join(dir, t.Name()[len("TestFileError/"):]+".json")
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return name
+	}
+
+	t.Run("syntactic", func(t *testing.T) {
+		name := write(t, "{\n\t\"name\": \"foo\",\n\t\"duration\": 3,,\n}\n")
+
+		_, err := jsonutil.ReadFile[config](name, jsonOpts)
+
+		errFile := &jsonutil.FileError{}
+		if !errors.As(err, &errFile) {
+			t.Fatalf("expected error to be a file error, got: %v", err)
+		}
+
+		if errFile.Path != name || errFile.Line != 3 || errFile.Column != 16 {
+			t.Fatalf("unexpected file error: %#v", errFile)
+		}
+
+		if want := "\t\"duration\": 3,,\n\t              ^"; errFile.Excerpt != want {
+			t.Fatalf("want excerpt:\n%s\ngot:\n%s", want, errFile.Excerpt)
+		}
+
+		errSyn := &jsontext.SyntacticError{}
+		if !errors.As(err, &errSyn) {
+			t.Fatalf("expected error to be a syntactic error, got: %v", err)
+		}
+
+		if want := name + ":3:16: " + errSyn.Error(); err.Error() != want {
+			t.Fatalf("want: %s, got: %s", want, err)
+		}
+	})
+
+	t.Run("EOF", func(t *testing.T) {
+		name := write(t, `{"name":`)
+
+		errFile := &jsonutil.FileError{}
+		if _, err := jsonutil.ReadFile[config](name, jsonOpts); !errors.As(err, &errFile) {
+			t.Fatalf("expected error to be a file error, got: %v", err)
+		} else if errFile.Line != 1 || errFile.Column != 9 || errFile.Excerpt != "{\"name\":\n        ^" {
+			t.Fatalf("unexpected file error: %#v", errFile)
+		}
+	})
+
+	t.Run("long line", func(t *testing.T) {
+		before := `{"name":"` + strings.Repeat("a", 100) + `","duration":3,`
+		after := `,"other":"` + strings.Repeat("b", 100) + `"}`
+		name := write(t, before+after)
+
+		errFile := &jsonutil.FileError{}
+		if _, err := jsonutil.ReadFile[config](name, jsonOpts); !errors.As(err, &errFile) {
+			t.Fatalf("expected error to be a file error, got: %v", err)
+		}
+
+		if errFile.Line != 1 || errFile.Column != len(before)+1 {
+			t.Fatalf("unexpected file error: %#v", errFile)
+		}
+
+		want := "..." + before[len(before)-40:] + after[:40] + "...\n" + strings.Repeat(" ", 43) + "^"
+		if errFile.Excerpt != want {
+			t.Fatalf("want excerpt:\n%s\ngot:\n%s", want, errFile.Excerpt)
+		}
+	})
+
+	t.Run("semantic", func(t *testing.T) {
+		name := write(t, "{\r\n  \"name\": \"ä\", \"duration\": \"3\"\r\n}")
+
+		_, err := jsonutil.ReadFile[config](name, jsonOpts)
+
+		errFile := &jsonutil.FileError{}
+		if !errors.As(err, &errFile) {
+			t.Fatalf("expected error to be a file error, got: %v", err)
+		}
+
+		if errFile.Line != 2 || errFile.Column != 28 || errFile.JSONPointer != "/duration" {
+			t.Fatalf("unexpected file error: %#v", errFile)
+		}
+
+		if want := "  \"name\": \"ä\", \"duration\": \"3\"\n                           ^"; errFile.Excerpt != want {
+			t.Fatalf("want excerpt:\n%s\ngot:\n%s", want, errFile.Excerpt)
+		}
+
+		errSem := &json.SemanticError{}
+		if !errors.As(err, &errSem) {
+			t.Fatalf("expected error to be a semantic error, got: %v", err)
+		} else if tpInt := reflect.TypeFor[int64](); errSem.GoType != tpInt {
+			t.Fatalf("expected semantic error to have type %s, got: %s", tpInt, errSem.GoType)
+		}
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		errFile := &jsonutil.FileError{}
+		if _, err := jsonutil.ReadFile[config](filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist error, got: %v", err)
+		} else if errors.As(err, &errFile) {
+			t.Fatalf("expected no file error, got: %v", err)
+		}
+	})
+
+	t.Run("write", func(t *testing.T) {
+		name := filepath.Join(dir, "write.json")
+
+		err := jsonutil.WriteFile(name, map[string]any{"ch": make(chan int)})
+
+		errFile := &jsonutil.FileError{}
+		if !errors.As(err, &errFile) {
+			t.Fatalf("expected error to be a file error, got: %v", err)
+		}
+
+		if errFile.Path != name || errFile.Line != 0 || errFile.JSONPointer != "/ch" {
+			t.Fatalf("unexpected file error: %#v", errFile)
+		}
+
+		if want := name + ": " + errFile.Err.Error(); err.Error() != want {
+			t.Fatalf("want: %s, got: %s", want, err)
+		}
+
+		if _, err := os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected file to be removed, got: %v", err)
+		}
+	})
+}
@@ -83,3 +83,17 @@ func HTTPHeaderUnmarshal(dec *jsontext.Decoder, h *http.Header) error {
 	_, err = dec.ReadToken() // consume jsontext.KindEndObject
 	return err
 }
+
+// SingleValueHeader is an http.Header that is marshaled with single string values,
+// see HTTPHeaderMarshal and HTTPHeaderUnmarshal.
+type SingleValueHeader http.Header
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (h SingleValueHeader) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return HTTPHeaderMarshal(enc, http.Header(h))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (h *SingleValueHeader) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return HTTPHeaderUnmarshal(dec, (*http.Header)(h))
+}
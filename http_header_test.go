@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
 )
 
 func TestHTTPHeader(t *testing.T) {
@@ -103,3 +104,58 @@ func TestHTTPHeader(t *testing.T) {
 		})
 	}
 }
+
+// nonEmptyHeader returns the keys and first values of h that HTTPHeaderMarshal encodes.
+func nonEmptyHeader(h http.Header) map[string]string {
+	m := map[string]string{}
+	for k, v := range h {
+		if len(v) > 0 && v[0] != "" {
+			m[http.CanonicalHeaderKey(k)] = v[0]
+		}
+	}
+
+	return m
+}
+
+func FuzzHTTPHeader(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, jsontest.Codec[http.Header]{
+		Marshal:   jsonutil.HTTPHeaderMarshal,
+		Unmarshal: jsonutil.HTTPHeaderUnmarshal,
+		// keys without a value are omitted
+		Equal: func(a, b http.Header) bool {
+			return (a == nil) == (b == nil) && maps.Equal(nonEmptyHeader(a), nonEmptyHeader(b))
+		},
+	},
+		`{"Content-Type":"application/json"}`, `{"x-lower":"a","X-Empty":""}`, `{}`, `null`, `{"a":1}`,
+	)
+}
+
+func TestSingleValueHeader(t *testing.T) {
+	type request struct {
+		Header jsonutil.SingleValueHeader `json:"header"`
+	}
+
+	v := request{Header: jsonutil.SingleValueHeader{"Accept": {"text/plain"}, "X-Empty": {}}}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"header":{"Accept":"text/plain"}}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out request
+	if err := json.Unmarshal([]byte(`{"header":{"content-type":"application/json"}}`), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := http.Header(out.Header).Get("Content-Type"); got != "application/json" {
+		t.Fatalf("want: application/json, got: %s", got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"header":{"a":["b"]}}`), &out); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,87 @@
+package jsontest
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"flag"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+var seed = flag.Uint64("jsontest.seed", 0, "the random seed of jsontest.CheckRoundTrip, random if 0")
+
+// Codec is a pair of custom marshal and unmarshal functions for values of type T,
+// e.g. jsonutil.URLMarshal and jsonutil.URLUnmarshal.
+// If either function is nil, the default encoding of T is used for that direction.
+type Codec[T any] struct {
+	Marshal   func(*jsontext.Encoder, T) error
+	Unmarshal func(*jsontext.Decoder, *T) error
+	// Equal reports whether two values are equal, e.g. for codecs that are lossy by design.
+	// If nil, the Equal method of T or reflect.DeepEqual is used, see RoundTrip.
+	Equal func(a, b T) bool
+}
+
+// Options returns the JSON options that use the codec.
+func (c Codec[T]) Options() json.Options {
+	var opts []json.Options
+	if c.Marshal != nil {
+		opts = append(opts, json.WithMarshalers(json.MarshalToFunc(c.Marshal)))
+	}
+
+	if c.Unmarshal != nil {
+		opts = append(opts, json.WithUnmarshalers(json.UnmarshalFromFunc(c.Unmarshal)))
+	}
+
+	return json.JoinOptions(opts...)
+}
+
+func (c Codec[T]) equal(a, b T) bool {
+	if c.Equal != nil {
+		return c.Equal(a, b)
+	}
+
+	return equal(a, b)
+}
+
+// CheckRoundTrip checks that decoding the encoding of a value returns an equal value
+// for n values produced by gen. The random seed is reported on failure.
+// To replay a failure, run the test with the -jsontest.seed flag set to that seed.
+func CheckRoundTrip[T any](t testing.TB, c Codec[T], gen func(*rand.Rand) T, n int) {
+	t.Helper()
+
+	s := *seed
+	if s == 0 {
+		s = uint64(time.Now().UnixNano())
+	}
+
+	r := rand.New(rand.NewPCG(s, s))
+	opts := c.Options()
+
+	for range n {
+		if !roundTrip(t, gen(r), c.equal, opts) {
+			t.Logf("seed: %d, replay with -jsontest.seed=%d", s, s)
+			return
+		}
+	}
+}
+
+// FuzzRoundTrip registers a fuzz target that checks that any value decoded from the fuzzed input
+// survives a round trip, i.e. decode(encode(decode(input))) == decode(input).
+// Inputs the codec rejects are skipped. The seeds are added to the seed corpus.
+func FuzzRoundTrip[T any](f *testing.F, c Codec[T], seeds ...string) {
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	opts := c.Options()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v T
+		if err := json.Unmarshal(data, &v, opts); err != nil {
+			return
+		}
+
+		roundTrip(t, v, c.equal, opts)
+	})
+}
@@ -0,0 +1,112 @@
+package jsontest_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"flag"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
+)
+
+var durationSeconds = jsontest.Codec[time.Duration]{
+	Marshal:   jsonutil.DurationMarshalIntSeconds,
+	Unmarshal: jsonutil.DurationUnmarshalIntSeconds,
+}
+
+func TestCodec(t *testing.T) {
+	t.Run("options", func(t *testing.T) {
+		b, err := json.Marshal(90*time.Second, durationSeconds.Options())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(b) != "90" {
+			t.Fatalf("want: 90, got: %s", b)
+		}
+
+		// no custom functions
+		b, err = json.Marshal(90, jsontest.Codec[int]{}.Options())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(b) != "90" {
+			t.Fatalf("want: 90, got: %s", b)
+		}
+	})
+
+	t.Run("CheckRoundTrip", func(t *testing.T) {
+		jsontest.CheckRoundTrip(t, durationSeconds, func(r *rand.Rand) time.Duration {
+			return time.Duration(r.Int64N(1<<32)) * time.Second
+		}, 100)
+	})
+
+	t.Run("CheckRoundTrip lossy", func(t *testing.T) {
+		r := run(t, func(t testing.TB) {
+			jsontest.CheckRoundTrip(t, durationSeconds, func(r *rand.Rand) time.Duration {
+				return time.Duration(r.Int64N(1<<32))*time.Second + time.Millisecond
+			}, 100)
+		})
+		if !r.failed || !strings.Contains(r.msg, "round trip via") {
+			t.Fatalf("expected round trip failure, got: %s", r.msg)
+		}
+	})
+
+	t.Run("CheckRoundTrip seed", func(t *testing.T) {
+		if err := flag.Set("jsontest.seed", "42"); err != nil {
+			t.Fatal(err)
+		}
+		defer flag.Set("jsontest.seed", "0")
+
+		var runs [2][]time.Duration
+		for i := range runs {
+			jsontest.CheckRoundTrip(t, durationSeconds, func(r *rand.Rand) time.Duration {
+				d := time.Duration(r.Int64N(1<<32)) * time.Second
+				runs[i] = append(runs[i], d)
+				return d
+			}, 10)
+		}
+
+		if !slices.Equal(runs[0], runs[1]) {
+			t.Fatalf("expected the same values for the same seed, got: %v and %v", runs[0], runs[1])
+		}
+
+		r := run(t, func(t testing.TB) {
+			jsontest.CheckRoundTrip(t, durationSeconds, func(*rand.Rand) time.Duration { return time.Millisecond }, 1)
+		})
+		if !r.failed || !strings.Contains(r.msg, "-jsontest.seed=42") {
+			t.Fatalf("expected failure mentioning the seed, got: %s", r.msg)
+		}
+	})
+
+	t.Run("CheckRoundTrip custom equal", func(t *testing.T) {
+		c := durationSeconds
+		c.Equal = func(a, b time.Duration) bool { return a.Truncate(time.Second) == b }
+
+		jsontest.CheckRoundTrip(t, c, func(r *rand.Rand) time.Duration {
+			return time.Duration(r.Int64N(1 << 42))
+		}, 100)
+	})
+
+	t.Run("CheckRoundTrip unmarshal error", func(t *testing.T) {
+		c := durationSeconds
+		c.Unmarshal = func(*jsontext.Decoder, *time.Duration) error { return jsontext.ErrDuplicateName }
+
+		r := run(t, func(t testing.TB) {
+			jsontest.CheckRoundTrip(t, c, func(*rand.Rand) time.Duration { return 0 }, 1)
+		})
+		if !r.failed || !strings.Contains(r.msg, "unmarshal") {
+			t.Fatalf("expected unmarshal failure, got: %s", r.msg)
+		}
+	})
+}
+
+func FuzzCodec(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, durationSeconds, `0`, `1`, `-1`, `3600`, `-10000000000`, `"1s"`, `null`)
+}
@@ -0,0 +1,127 @@
+// Package jsontest provides helpers for testing JSON encoding and decoding.
+package jsontest
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+// update is namespaced, so that it doesn't clash with an -update flag of the package under test.
+var update = flag.Bool("jsontest.update", false, "update the golden files of jsontest.Golden")
+
+// AssertJSONEqual reports an error if want and got are not semantically equal JSON values,
+// i.e. if they differ in more than the order of object members, whitespace,
+// string escaping or the representation of numbers, e.g. 1 and 1.0.
+// Numbers are compared exactly, so e.g. int64 IDs that float64 can't tell apart are reported.
+func AssertJSONEqual(t testing.TB, want, got []byte) {
+	t.Helper()
+	assertJSONEqual(t, want, got)
+}
+
+// assertJSONEqual implements AssertJSONEqual and reports whether the values are equal.
+func assertJSONEqual(t testing.TB, want, got []byte) bool {
+	t.Helper()
+
+	diffs, err := jsonutil.Diff(want, got, jsonutil.IgnoreKeyOrder(), jsonutil.IgnoreNumberFormat())
+	if err != nil {
+		t.Errorf("cannot compare JSON: %v", err)
+		return false
+	}
+
+	if len(diffs) > 0 {
+		t.Errorf("JSON not equal (- want, + got):\n%s", diffs)
+		return false
+	}
+
+	return true
+}
+
+// Golden compares the JSON representation of v with the golden file testdata/<name>.json.
+// If the test is run with the -jsontest.update flag, the golden file is written instead.
+func Golden(t testing.TB, name string, v any, opts ...json.Options) {
+	t.Helper()
+
+	got, err := json.Marshal(v, append(opts, jsontext.Multiline(true))...)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	path := filepath.Join("testdata", name+".json")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, append(got, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist, run the test with -jsontest.update to create it", path)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertJSONEqual(t, want, got)
+}
+
+// RoundTrip marshals v, unmarshals the result into a new value of type T and reports an error
+// if it is not deeply equal to v, using the given options for both.
+// If T has a method Equal(T) bool, like time.Time, it is used instead of reflect.DeepEqual.
+// It also checks that marshaling the new value produces the same JSON.
+func RoundTrip[T any](t testing.TB, v T, opts ...json.Options) {
+	t.Helper()
+	roundTrip(t, v, equal, json.JoinOptions(opts...))
+}
+
+// roundTrip implements RoundTrip and reports whether it succeeded.
+func roundTrip[T any](t testing.TB, v T, equal func(a, b T) bool, opts json.Options) bool {
+	t.Helper()
+
+	b, err := json.Marshal(v, opts)
+	if err != nil {
+		t.Errorf("marshal %#v: %v", v, err)
+		return false
+	}
+
+	var out T
+	if err := json.Unmarshal(b, &out, opts); err != nil {
+		t.Errorf("unmarshal %s: %v", b, err)
+		return false
+	}
+
+	if !equal(v, out) {
+		t.Errorf("round trip via %s: want: %#v, got: %#v", b, v, out)
+		return false
+	}
+
+	again, err := json.Marshal(out, opts)
+	if err != nil {
+		t.Errorf("marshal %#v: %v", out, err)
+		return false
+	}
+
+	return assertJSONEqual(t, b, again)
+}
+
+func equal[T any](a, b T) bool {
+	if eq, ok := any(a).(interface{ Equal(T) bool }); ok {
+		return eq.Equal(b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
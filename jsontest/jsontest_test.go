@@ -0,0 +1,199 @@
+package jsontest_test
+
+import (
+	"encoding/json/v2"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
+)
+
+// packages using jsontest may define their own -update flag
+var _ = flag.Bool("update", false, "update the golden files of the package under test")
+
+// recorder records the failures of a test helper instead of failing the test.
+type recorder struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+	r.msg += fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Logf(format string, args ...any) {
+	r.msg += fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+func (r *recorder) Fatal(args ...any) {
+	r.Errorf("%s", fmt.Sprint(args...))
+	runtime.Goexit()
+}
+
+// run runs f with a recorder and returns it.
+func run(t *testing.T, f func(testing.TB)) *recorder {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+
+	<-done
+	return r
+}
+
+func TestAssertJSONEqual(t *testing.T) {
+	for _, tc := range []struct{ want, got string }{
+		{`{"a":1,"b":[true,null]}`, `{ "b": [true, null], "a": 1.0 }`},
+		{`"é"`, `"é"`},
+	} {
+		if r := run(t, func(t testing.TB) {
+			jsontest.AssertJSONEqual(t, []byte(tc.want), []byte(tc.got))
+		}); r.failed {
+			t.Fatalf("unexpected failure: %s", r.msg)
+		}
+	}
+
+	for _, tc := range []struct{ want, got, msg string }{
+		{`{"a":1}`, `{"a":2}`, "@@ /a @@\n- 1\n+ 2\n"},
+		{`[1,2]`, `[2,1]`, "@@ /0 @@"},
+		{`{"id":9007199254740993}`, `{"id":9007199254740992.0}`, "@@ /id @@"},
+		{`{`, `{}`, "cannot compare JSON"},
+	} {
+		r := run(t, func(t testing.TB) {
+			jsontest.AssertJSONEqual(t, []byte(tc.want), []byte(tc.got))
+		})
+		if !r.failed {
+			t.Fatalf("expected failure for %s and %s", tc.want, tc.got)
+		}
+
+		if !strings.Contains(r.msg, tc.msg) {
+			t.Fatalf("expected message to contain %q, got: %s", tc.msg, r.msg)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	type config struct {
+		Name  string `json:"name"`
+		Ports []int  `json:"ports"`
+	}
+
+	v := config{Name: "server", Ports: []int{80, 443}}
+
+	t.Run("missing", func(t *testing.T) {
+		r := run(t, func(t testing.TB) { jsontest.Golden(t, "config", v) })
+		if !r.failed || !strings.Contains(r.msg, "-jsontest.update") {
+			t.Fatalf("expected failure mentioning -jsontest.update, got: %s", r.msg)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		if err := flag.Set("jsontest.update", "true"); err != nil {
+			t.Fatal(err)
+		}
+		defer flag.Set("jsontest.update", "false")
+
+		jsontest.Golden(t, "config", v)
+
+		b, err := os.ReadFile(filepath.Join("testdata", "config.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := "{\n\t\"name\": \"server\",\n\t\"ports\": [\n\t\t80,\n\t\t443\n\t]\n}\n"; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		jsontest.Golden(t, "config", v)
+	})
+
+	t.Run("different", func(t *testing.T) {
+		r := run(t, func(t testing.TB) {
+			jsontest.Golden(t, "config", config{Name: "client", Ports: []int{80, 443}})
+		})
+		if !r.failed || !strings.Contains(r.msg, `+ "client"`) {
+			t.Fatalf("expected failure with difference, got: %s", r.msg)
+		}
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		if r := run(t, func(t testing.TB) { jsontest.Golden(t, "config", make(chan int)) }); !r.failed {
+			t.Fatalf("expected failure")
+		}
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	type testURL struct {
+		URL url.URL `json:"url"`
+	}
+
+	urlOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.URLMarshal)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.URLUnmarshal)),
+	)
+
+	jsontest.RoundTrip(t, testURL{URL: url.URL{Scheme: "https", Host: "example.com", Path: "/path"}}, urlOpts)
+	jsontest.RoundTrip(t, map[string][]int{"a": {1, 2}})
+
+	// uses time.Time.Equal, so the loss of the monotonic clock reading doesn't matter
+	jsontest.RoundTrip(t, time.Now())
+
+	t.Run("lossy", func(t *testing.T) {
+		timeOpts := json.JoinOptions(
+			json.WithMarshalers(json.MarshalToFunc(jsonutil.TimeMarshalIntUnix)),
+			json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.TimeUnmarshalIntUnix)),
+		)
+
+		r := run(t, func(t testing.TB) {
+			jsontest.RoundTrip(t, time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), timeOpts)
+		})
+		if !r.failed || !strings.Contains(r.msg, "round trip via 1704164645") {
+			t.Fatalf("expected round trip failure, got: %s", r.msg)
+		}
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		type onlyMarshal struct {
+			URL url.URL `json:"url"`
+		}
+
+		marshalOnly := json.WithMarshalers(json.MarshalToFunc(jsonutil.URLMarshal))
+
+		if r := run(t, func(t testing.TB) {
+			jsontest.RoundTrip(t, onlyMarshal{}, marshalOnly)
+		}); !r.failed || !strings.Contains(r.msg, "unmarshal") {
+			t.Fatalf("expected unmarshal failure, got: %s", r.msg)
+		}
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		if r := run(t, func(t testing.TB) { jsontest.RoundTrip(t, make(chan int)) }); !r.failed {
+			t.Fatalf("expected failure")
+		}
+	})
+}
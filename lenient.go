@@ -0,0 +1,233 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultTrueSpellings and DefaultFalseSpellings are the spellings of booleans that BoolUnmarshalLenient accepts.
+var (
+	DefaultTrueSpellings  = []string{"true", "t", "yes", "y", "on", "1"}
+	DefaultFalseSpellings = []string{"false", "f", "no", "n", "off", "0"}
+)
+
+// BoolUnmarshalLenient is a custom unmarshaler for bool, unmarshaling them from JSON booleans,
+// the numbers 1 and 0, and strings in DefaultTrueSpellings or DefaultFalseSpellings, e.g. "Yes" or "N".
+// Strings are matched regardless of case and surrounding whitespace. Nulls are ignored.
+func BoolUnmarshalLenient(dec *jsontext.Decoder, b *bool) error {
+	return BoolUnmarshalSpellings(DefaultTrueSpellings, DefaultFalseSpellings)(dec, b)
+}
+
+// BoolUnmarshalSpellings returns a custom unmarshaler for bool like BoolUnmarshalLenient
+// that accepts the given spellings instead. Numbers are accepted if their text is one of the spellings.
+// It panics if a spelling is both true and false.
+func BoolUnmarshalSpellings(trueSpellings, falseSpellings []string) func(*jsontext.Decoder, *bool) error {
+	spellings := make(map[string]bool, len(trueSpellings)+len(falseSpellings))
+	for _, s := range trueSpellings {
+		spellings[strings.ToLower(s)] = true
+	}
+
+	for _, s := range falseSpellings {
+		if spellings[strings.ToLower(s)] {
+			panic(fmt.Sprintf("jsonutil: %q is both a true and a false spelling", s))
+		}
+
+		spellings[strings.ToLower(s)] = false
+	}
+
+	return func(dec *jsontext.Decoder, b *bool) error {
+		tkn, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+
+		switch tkn.Kind() {
+		case jsontext.KindTrue, jsontext.KindFalse:
+			*b = tkn.Bool()
+		case jsontext.KindString, jsontext.KindNumber:
+			v, ok := spellings[strings.ToLower(strings.TrimSpace(tkn.String()))]
+			if !ok {
+				return fmt.Errorf("cannot unmarshal %q into a bool", tkn.String())
+			}
+
+			*b = v
+		case jsontext.KindNull: // ok, nothing to do
+		default:
+			return fmt.Errorf("unknown token kind %s", tkn.Kind())
+		}
+
+		return nil
+	}
+}
+
+// NumberLocale describes how a locale formats numbers in strings.
+type NumberLocale struct {
+	// Thousands separates groups of three digits in the integer part, e.g. ',' in "1,234", or 0 for none.
+	// Grouping is optional, but if a separator is present, the groups must be of three digits.
+	Thousands rune
+	// Decimal separates the integer part from the fraction, e.g. '.' in "1.5".
+	Decimal rune
+}
+
+// Common number locales.
+var (
+	NumberLocaleEnglish = NumberLocale{Thousands: ',', Decimal: '.'}      // e.g. 1,234.5
+	NumberLocaleGerman  = NumberLocale{Thousands: '.', Decimal: ','}      // e.g. 1.234,5
+	NumberLocaleSwiss   = NumberLocale{Thousands: '\'', Decimal: '.'}     // e.g. 1'234.5
+	NumberLocaleFrench  = NumberLocale{Thousands: '\u202f', Decimal: ','} // e.g. 1 234,5 with a narrow no-break space
+)
+
+// Int64UnmarshalLenient is a custom unmarshaler for int64, unmarshaling them from JSON numbers and strings
+// formatted according to NumberLocaleEnglish, e.g. "1,234". Nulls are ignored.
+func Int64UnmarshalLenient(dec *jsontext.Decoder, i *int64) error {
+	return Int64UnmarshalLocale(NumberLocaleEnglish)(dec, i)
+}
+
+// Int64UnmarshalLocale returns a custom unmarshaler for int64 like Int64UnmarshalLenient
+// that accepts strings formatted according to the given locale instead.
+// Numbers with a fraction of zero, e.g. "1,000.00", are accepted. They are parsed exactly, never rounded.
+func Int64UnmarshalLocale(l NumberLocale) func(*jsontext.Decoder, *int64) error {
+	return func(dec *jsontext.Decoder, i *int64) error {
+		s, null, err := readLenientNumber(dec, l)
+		if err != nil || null {
+			return err
+		}
+
+		// e.g. 1.00
+		if intPart, frac, ok := strings.Cut(s, "."); ok && strings.Trim(frac, "0") == "" {
+			s = intPart
+		}
+
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			*i = v
+			return nil
+		}
+
+		// e.g. 1.5e3, which is parsed exactly to never round large integers
+		r, ok := new(big.Rat).SetString(s)
+		if !ok || !r.IsInt() || !r.Num().IsInt64() {
+			return fmt.Errorf("cannot unmarshal %q into an int64", s)
+		}
+
+		*i = r.Num().Int64()
+		return nil
+	}
+}
+
+// Float64UnmarshalLenient is a custom unmarshaler for float64, unmarshaling them from JSON numbers and strings
+// formatted according to NumberLocaleEnglish, e.g. "1,234.5". Nulls are ignored.
+func Float64UnmarshalLenient(dec *jsontext.Decoder, f *float64) error {
+	return Float64UnmarshalLocale(NumberLocaleEnglish)(dec, f)
+}
+
+// Float64UnmarshalLocale returns a custom unmarshaler for float64 like Float64UnmarshalLenient
+// that accepts strings formatted according to the given locale instead.
+func Float64UnmarshalLocale(l NumberLocale) func(*jsontext.Decoder, *float64) error {
+	return func(dec *jsontext.Decoder, f *float64) error {
+		s, null, err := readLenientNumber(dec, l)
+		if err != nil || null {
+			return err
+		}
+
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal %q into a float64: %w", s, err)
+		}
+
+		*f = v
+		return nil
+	}
+}
+
+// readLenientNumber reads a number or a string formatted according to the locale
+// and returns it in the format of JSON numbers, or reports whether it read a null.
+func readLenientNumber(dec *jsontext.Decoder, l NumberLocale) (string, bool, error) {
+	tkn, err := dec.ReadToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	switch tkn.Kind() {
+	case jsontext.KindNumber:
+		return tkn.String(), false, nil
+	case jsontext.KindString:
+		s, err := normalizeNumber(tkn.String(), l)
+		return s, false, err
+	case jsontext.KindNull:
+		return "", true, nil
+	default:
+		return "", false, fmt.Errorf("unknown token kind %s", tkn.Kind())
+	}
+}
+
+// normalizeNumber removes the thousands separators from a number formatted according to the locale
+// and replaces its decimal separator with '.'.
+func normalizeNumber(s string, l NumberLocale) (string, error) {
+	s = strings.TrimSpace(s)
+
+	intPart, frac, hasFrac := strings.Cut(s, string(l.Decimal))
+
+	unsigned := trimSign(intPart)
+	sign, intPart := intPart[:len(intPart)-len(unsigned)], unsigned
+
+	if l.Thousands != 0 && strings.ContainsRune(intPart, l.Thousands) {
+		groups := strings.Split(intPart, string(l.Thousands))
+		for i, g := range groups {
+			if len(g) > 3 || len(g) < 3 && i > 0 || g == "" {
+				return "", fmt.Errorf("invalid digit grouping in %q", s)
+			}
+		}
+
+		intPart = strings.Join(groups, "")
+	}
+
+	n := sign + intPart
+	if hasFrac {
+		n += "." + frac
+	}
+
+	if !isNumberText(n) {
+		return "", fmt.Errorf("invalid number %q", s)
+	}
+
+	return n, nil
+}
+
+// isNumberText reports whether s consists of an optional sign, digits, an optional fraction and exponent.
+// Unlike JSON numbers, a leading '+', leading zeros and a missing integer part or fraction are accepted.
+func isNumberText(s string) bool {
+	mantissa, exp, hasExp := strings.Cut(strings.ToLower(trimSign(s)), "e")
+	intPart, frac, hasFrac := strings.Cut(mantissa, ".")
+
+	if intPart == "" && (!hasFrac || frac == "") || !isDigits(intPart) || !isDigits(frac) {
+		return false
+	}
+
+	if hasExp {
+		exp = trimSign(exp)
+		return exp != "" && isDigits(exp)
+	}
+
+	return true
+}
+
+func trimSign(s string) string {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		return s[1:]
+	}
+
+	return s
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,191 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestBoolUnmarshalLenient(t *testing.T) {
+	opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.BoolUnmarshalLenient))
+
+	for in, want := range map[string]bool{
+		`true`: true, `false`: false, `"true"`: true, `"yes"`: true, `"Y"`: true, `" On "`: true,
+		`"1"`: true, `1`: true, `"F"`: false, `"no"`: false, `"0"`: false, `0`: false,
+	} {
+		t.Run(in, func(t *testing.T) {
+			out := !want
+			if err := json.Unmarshal([]byte(in), &out, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out != want {
+				t.Fatalf("want: %t, got: %t", want, out)
+			}
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		out := true
+		if err := json.Unmarshal([]byte(`null`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !out {
+			t.Fatalf("expected value to be unchanged")
+		}
+	})
+
+	for _, in := range []string{`"maybe"`, `""`, `2`, `1.0`, `[]`, `{}`} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out bool
+			if err := json.Unmarshal([]byte(in), &out, opts); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+
+	t.Run("spellings", func(t *testing.T) {
+		opts := json.WithUnmarshalers(json.UnmarshalFromFunc(
+			jsonutil.BoolUnmarshalSpellings([]string{"ja", "1"}, []string{"Nein", "-1"}),
+		))
+
+		var v struct{ A, B, C, D bool }
+		if err := json.Unmarshal([]byte(`{"A":"JA","B":"nein","C":1,"D":true}`), &v, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !v.A || v.B || !v.C || !v.D {
+			t.Fatalf("unexpected result: %+v", v)
+		}
+
+		if err := json.Unmarshal([]byte(`-1`), &v.A, opts); err != nil || v.A {
+			t.Fatalf("want: false, got: %t (%v)", v.A, err)
+		}
+
+		if err := json.Unmarshal([]byte(`"yes"`), &v.A, opts); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("ambiguous spellings", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic")
+			}
+		}()
+
+		jsonutil.BoolUnmarshalSpellings([]string{"x"}, []string{"X"})
+	})
+}
+
+func TestInt64UnmarshalLenient(t *testing.T) {
+	opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.Int64UnmarshalLenient))
+
+	for in, want := range map[string]int64{
+		`42`: 42, `-7`: -7, `1e3`: 1000, `"42"`: 42, `" +1,234 "`: 1234, `"-1,234,567"`: -1234567,
+		`"1,000.00"`: 1000, `"1234567"`: 1234567, `"9,223,372,036,854,775,807"`: 9223372036854775807,
+		`"9,007,199,254,740,993.0"`: 9007199254740993, `"9007199254740993.00"`: 9007199254740993,
+		`9007199254740993.0`: 9007199254740993, `"9.007199254740993e15"`: 9007199254740993,
+		`"-9223372036854775808.0"`: -9223372036854775808, `"5."`: 5, `"1.5e3"`: 1500,
+	} {
+		t.Run(in, func(t *testing.T) {
+			var out int64
+			if err := json.Unmarshal([]byte(in), &out, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out != want {
+				t.Fatalf("want: %d, got: %d", want, out)
+			}
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		out := int64(3)
+		if err := json.Unmarshal([]byte(`null`), &out, opts); err != nil || out != 3 {
+			t.Fatalf("want: 3, got: %d (%v)", out, err)
+		}
+	})
+
+	for _, in := range []string{
+		`1.5`, `"1.5"`, `"12,34"`, `"1,2345"`, `",123"`, `"1,,234"`, `"1.234"`, `"abc"`, `""`,
+		`"--1"`, `"9,223,372,036,854,775,808"`, `1e19`, `true`, `[]`,
+		`"9.0071992547409935e15"`, `"9223372036854775808.0"`, `9.223372036854775808e18`,
+	} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out int64
+			if err := json.Unmarshal([]byte(in), &out, opts); err == nil {
+				t.Fatalf("expected error, got: %d", out)
+			}
+		})
+	}
+
+	t.Run("locale", func(t *testing.T) {
+		opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.Int64UnmarshalLocale(jsonutil.NumberLocaleGerman)))
+
+		var out int64
+		if err := json.Unmarshal([]byte(`"1.234.567,0"`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != 1234567 {
+			t.Fatalf("want: 1234567, got: %d", out)
+		}
+	})
+}
+
+func TestFloat64UnmarshalLenient(t *testing.T) {
+	opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.Float64UnmarshalLenient))
+
+	for in, want := range map[string]float64{
+		`1.5`: 1.5, `"1,234.5"`: 1234.5, `"-0.25"`: -0.25, `".5"`: 0.5, `"5."`: 5, `"1.5e3"`: 1500,
+		`"1,234"`: 1234, `" 12 "`: 12,
+	} {
+		t.Run(in, func(t *testing.T) {
+			var out float64
+			if err := json.Unmarshal([]byte(in), &out, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out != want {
+				t.Fatalf("want: %v, got: %v", want, out)
+			}
+		})
+	}
+
+	for _, in := range []string{`"NaN"`, `"Inf"`, `"1,5"`, `"."`, `"1e"`, `"0x10"`, `"1e400"`, `false`} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out float64
+			if err := json.Unmarshal([]byte(in), &out, opts); err == nil {
+				t.Fatalf("expected error, got: %v", out)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		locale jsonutil.NumberLocale
+		in     string
+		want   float64
+	}{
+		{"German", jsonutil.NumberLocaleGerman, `"1.234,5"`, 1234.5},
+		{"Swiss", jsonutil.NumberLocaleSwiss, `"1'234.5"`, 1234.5},
+		{"French", jsonutil.NumberLocaleFrench, "\"-1\u202f234,5\"", -1234.5},
+		{"no grouping", jsonutil.NumberLocale{Decimal: ','}, `"1234,5"`, 1234.5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.Float64UnmarshalLocale(tc.locale)))
+
+			var out float64
+			if err := json.Unmarshal([]byte(tc.in), &out, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out != tc.want {
+				t.Fatalf("want: %v, got: %v", tc.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package jsonutil
+
+import "context"
+
+// lockFile is a no-op on platforms without flock.
+func lockFile(context.Context, string) (func() error, error) {
+	return func() error { return nil }, nil
+}
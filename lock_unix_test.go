@@ -0,0 +1,54 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package jsonutil_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestUpdateFileLockTimeout(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "state.json")
+
+	locked, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+
+	go func() {
+		done <- jsonutil.UpdateFile(name, func(n *int) error {
+			close(locked)
+			<-release
+			*n++
+			return nil
+		})
+	}()
+
+	<-locked
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := jsonutil.UpdateFileContext(ctx, name, func(n *int) error {
+		t.Fatalf("update must not be called while the lock is held")
+		return nil
+	}); !errors.Is(err, jsonutil.ErrLockTimeout) {
+		t.Fatalf("expected lock timeout, got: %v", err)
+	} else if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n, err := jsonutil.ReadFile[int](name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 1 {
+		t.Fatalf("want: 1, got: %d", n)
+	}
+}
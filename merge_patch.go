@@ -0,0 +1,202 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MergePatch applies a JSON Merge Patch, e.g. a request body of type application/merge-patch+json,
+// to target as described in RFC 7396: objects are merged member by member, a null member removes
+// the member from the target and any other value replaces the target.
+// The order of the members of the target is kept, new members are appended.
+func MergePatch(target, patch jsontext.Value) (jsontext.Value, error) {
+	if !patch.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", patch)
+	}
+
+	if target != nil && !target.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", target)
+	}
+
+	return mergePatch(target, patch, "", nil)
+}
+
+// mergeObserver is notified of the changes mergePatch makes to the target,
+// with v being the new value at ptr, or nil if the value at ptr is removed.
+type mergeObserver func(ptr jsontext.Pointer, v jsontext.Value)
+
+// mergePatch applies the patch to the target, which is the value at ptr, and notifies observe, if not nil, of the changes.
+func mergePatch(target, patch jsontext.Value, ptr jsontext.Pointer, observe mergeObserver) (jsontext.Value, error) {
+	if patch.Kind() != jsontext.KindBeginObject {
+		if observe != nil {
+			observe(ptr, patch)
+		}
+
+		return patch.Clone(), nil
+	}
+
+	var members []member
+	if target.Kind() == jsontext.KindBeginObject {
+		var err error
+		if members, err = readMembers(target); err != nil {
+			return nil, err
+		}
+	} else if observe != nil {
+		observe(ptr, nil) // the target is replaced by an object
+	}
+
+	patchMembers, err := readMembers(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pm := range patchMembers {
+		i := slices.IndexFunc(members, func(m member) bool { return m.name == pm.name })
+		memberPtr := ptr.AppendToken(pm.name)
+
+		switch {
+		case pm.value.Kind() == jsontext.KindNull:
+			if i >= 0 {
+				members = slices.Delete(members, i, i+1)
+			}
+
+			if observe != nil {
+				observe(memberPtr, nil)
+			}
+		case i >= 0:
+			if members[i].value, err = mergePatch(members[i].value, pm.value, memberPtr, observe); err != nil {
+				return nil, err
+			}
+		default:
+			v, err := mergePatch(nil, pm.value, memberPtr, observe)
+			if err != nil {
+				return nil, err
+			}
+
+			members = append(members, member{pm.name, v})
+		}
+	}
+
+	return encodeMembers(members)
+}
+
+// CreateMergePatch returns a JSON Merge Patch that transforms original into modified.
+// Since null removes members in a merge patch, it returns an error if modified contains
+// a null member that is not in original.
+func CreateMergePatch(original, modified jsontext.Value) (jsontext.Value, error) {
+	if !original.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", original)
+	}
+
+	if !modified.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", modified)
+	}
+
+	return createMergePatch(original, modified)
+}
+
+func createMergePatch(original, modified jsontext.Value) (jsontext.Value, error) {
+	if original.Kind() != jsontext.KindBeginObject || modified.Kind() != jsontext.KindBeginObject {
+		if err := checkNoNullMembers(modified); err != nil {
+			return nil, err
+		}
+
+		return modified.Clone(), nil
+	}
+
+	originalMembers, err := readMembers(original)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiedMembers, err := readMembers(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch []member
+	for _, om := range originalMembers {
+		if !slices.ContainsFunc(modifiedMembers, func(m member) bool { return m.name == om.name }) {
+			patch = append(patch, member{om.name, jsontext.Value("null")})
+		}
+	}
+
+	for _, mm := range modifiedMembers {
+		i := slices.IndexFunc(originalMembers, func(m member) bool { return m.name == mm.name })
+		if i >= 0 && equalValues(originalMembers[i].value, mm.value) {
+			continue
+		}
+
+		if mm.value.Kind() == jsontext.KindNull {
+			return nil, fmt.Errorf("cannot set %q to null in a merge patch", mm.name)
+		}
+
+		var original jsontext.Value
+		if i >= 0 {
+			original = originalMembers[i].value
+		}
+
+		v, err := createMergePatch(original, mm.value)
+		if err != nil {
+			return nil, err
+		}
+
+		patch = append(patch, member{mm.name, v})
+	}
+
+	return encodeMembers(patch)
+}
+
+// checkNoNullMembers returns an error if v is or contains an object with a null member.
+func checkNoNullMembers(v jsontext.Value) error {
+	if v.Kind() != jsontext.KindBeginObject {
+		return nil // arrays are replaced as a whole, so nulls inside them are preserved
+	}
+
+	members, err := readMembers(v)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		if m.value.Kind() == jsontext.KindNull {
+			return fmt.Errorf("cannot set %q to null in a merge patch", m.name)
+		}
+
+		if err := checkNoNullMembers(m.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MergePatchInto applies a JSON Merge Patch to the JSON representation of v.
+// The value is marshaled, patched and unmarshaled into a new T that replaces v, using the given options.
+// If an error occurs, v is left unchanged.
+func MergePatchInto[T any](v *T, patch jsontext.Value, opts ...json.Options) error {
+	if v == nil {
+		return errors.New("nil pointer")
+	}
+
+	target, err := json.Marshal(v, opts...)
+	if err != nil {
+		return err
+	}
+
+	patched, err := MergePatch(target, patch)
+	if err != nil {
+		return err
+	}
+
+	var out T
+	if err := json.Unmarshal(patched, &out, opts...); err != nil {
+		return err
+	}
+
+	*v = out
+	return nil
+}
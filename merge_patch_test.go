@@ -0,0 +1,166 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestMergePatch(t *testing.T) {
+	// examples from RFC 7396, appendix A
+	for _, tc := range []struct{ target, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// the order of the target's members is kept
+		{`{"z":1,"y":2,"x":3}`, `{"y":4,"w":5}`, `{"z":1,"y":4,"x":3,"w":5}`},
+	} {
+		got, err := jsonutil.MergePatch(jsontext.Value(tc.target), jsontext.Value(tc.patch))
+		if err != nil {
+			t.Fatalf("%s + %s: unexpected error: %v", tc.target, tc.patch, err)
+		}
+
+		if string(got) != tc.want {
+			t.Fatalf("%s + %s: want: %s, got: %s", tc.target, tc.patch, tc.want, got)
+		}
+	}
+
+	if got, err := jsonutil.MergePatch(nil, jsontext.Value(`{"a":null,"b":1}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if string(got) != `{"b":1}` {
+		t.Fatalf("want: {\"b\":1}, got: %s", got)
+	}
+
+	if _, err := jsonutil.MergePatch(jsontext.Value(`{}`), jsontext.Value(`{`)); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if _, err := jsonutil.MergePatch(jsontext.Value(`{`), jsontext.Value(`{}`)); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	for _, tc := range []struct{ original, modified, want string }{
+		{`{"a":"b"}`, `{"a":"b"}`, `{}`},
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b","b":"c"}`, `{"b":"c"}`, `{"a":null}`},
+		{`{"a":{"b":"c","d":1}}`, `{"a":{"b":"d","d":1.0}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[1,2]}`, `{"a":[1,3],"b":{"c":[null]}}`, `{"a":[1,3],"b":{"c":[null]}}`},
+		{`{"a":null}`, `{"a":null,"b":1}`, `{"b":1}`},
+		{`[1]`, `{"a":1}`, `{"a":1}`},
+		{`{"a":1}`, `null`, `null`},
+		{`{"id":9007199254740993}`, `{"id":9007199254740992}`, `{"id":9007199254740992}`},
+	} {
+		got, err := jsonutil.CreateMergePatch(jsontext.Value(tc.original), jsontext.Value(tc.modified))
+		if err != nil {
+			t.Fatalf("%s -> %s: unexpected error: %v", tc.original, tc.modified, err)
+		}
+
+		if string(got) != tc.want {
+			t.Fatalf("%s -> %s: want: %s, got: %s", tc.original, tc.modified, tc.want, got)
+		}
+
+		patched, err := jsonutil.MergePatch(jsontext.Value(tc.original), got)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !equalJSON(t, patched, jsontext.Value(tc.modified)) {
+			t.Fatalf("%s -> %s: patch %s results in %s", tc.original, tc.modified, got, patched)
+		}
+	}
+
+	for _, tc := range []struct{ original, modified string }{
+		{`{"a":1}`, `{"a":null}`},
+		{`{}`, `{"a":{"b":null}}`},
+		{`1`, `{"a":{"b":null}}`},
+		{`{`, `{}`},
+		{`{}`, `{`},
+	} {
+		if _, err := jsonutil.CreateMergePatch(jsontext.Value(tc.original), jsontext.Value(tc.modified)); err == nil {
+			t.Fatalf("%s -> %s: expected error", tc.original, tc.modified)
+		}
+	}
+}
+
+func TestMergePatchInto(t *testing.T) {
+	type settings struct {
+		Name    string            `json:"name"`
+		Timeout time.Duration     `json:"timeout"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Tags    []string          `json:"tags"`
+	}
+
+	jsonOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.DurationMarshalIntSeconds)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds)),
+	)
+
+	v := settings{
+		Name:    "foo",
+		Timeout: time.Minute,
+		Labels:  map[string]string{"a": "1", "b": "2"},
+		Tags:    []string{"x"},
+	}
+
+	if err := jsonutil.MergePatchInto(&v, jsontext.Value(`{"timeout":30,"labels":{"a":null,"c":"3"},"tags":null}`), jsonOpts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := settings{
+		Name:    "foo",
+		Timeout: 30 * time.Second,
+		Labels:  map[string]string{"b": "2", "c": "3"},
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("want: %+v, got: %+v", want, v)
+	}
+
+	t.Run("semantic error", func(t *testing.T) {
+		errSem := &json.SemanticError{}
+		if err := jsonutil.MergePatchInto(&v, jsontext.Value(`{"timeout":"1m"}`), jsonOpts); !errors.As(err, &errSem) {
+			t.Fatalf("expected error to be a semantic error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(v, want) {
+			t.Fatalf("expected value to be unchanged, got: %+v", v)
+		}
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		if err := jsonutil.MergePatchInto(&v, jsontext.Value(`{`), jsonOpts); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if err := jsonutil.MergePatchInto[settings](nil, jsontext.Value(`{}`)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		ch := map[string]any{"c": make(chan int)}
+		if err := jsonutil.MergePatchInto(&ch, jsontext.Value(`{}`)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
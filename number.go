@@ -0,0 +1,104 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Number is a JSON number that keeps its exact textual representation, e.g. "0.10" or "1e400",
+// so that it can be passed through without losing precision and compared without rounding.
+// It is unmarshaled from JSON numbers and from strings that hold one, and marshaled as a JSON number.
+// The zero value, which null unmarshals as, is marshaled as 0.
+type Number string
+
+// IsZero reports whether the number has no textual representation.
+func (n Number) IsZero() bool { return n == "" }
+
+// String returns the textual representation of the number.
+func (n Number) String() string { return string(n) }
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) { return strconv.ParseInt(n.text(), 10, 64) }
+
+// Float64 returns the number as a float64, which may be rounded.
+func (n Number) Float64() (float64, error) { return strconv.ParseFloat(n.text(), 64) }
+
+// Rat returns the exact value of the number.
+func (n Number) Rat() (*big.Rat, error) {
+	if err := n.validate(); err != nil {
+		return nil, err
+	}
+
+	r, ok := new(big.Rat).SetString(n.text())
+	if !ok {
+		return nil, fmt.Errorf("number %s out of range", n)
+	}
+
+	return r, nil
+}
+
+// Cmp compares the exact values of two numbers and returns -1, 0 or +1,
+// e.g. 1.50 and 15e-1 are equal.
+func (n Number) Cmp(m Number) (int, error) {
+	a, err := n.Rat()
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := m.Rat()
+	if err != nil {
+		return 0, err
+	}
+
+	return a.Cmp(b), nil
+}
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (n Number) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if err := n.validate(); err != nil {
+		return err
+	}
+
+	return enc.WriteValue(jsontext.Value(n.text()))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (n *Number) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	s, err := readNumberText(dec)
+	if err != nil {
+		return err
+	}
+
+	if s == "" { // null
+		*n = ""
+		return nil
+	}
+
+	if err := Number(s).validate(); err != nil {
+		return err
+	}
+
+	*n = Number(s)
+	return nil
+}
+
+func (n Number) text() string {
+	if n == "" {
+		return "0"
+	}
+
+	return string(n)
+}
+
+// validate returns an error if the number is not a valid JSON number.
+func (n Number) validate() error {
+	v := jsontext.Value(n.text())
+	if v.Kind() != jsontext.KindNumber || !v.IsValid() || strings.TrimSpace(string(v)) != string(v) {
+		return fmt.Errorf("invalid JSON number %q", string(n))
+	}
+
+	return nil
+}
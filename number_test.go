@@ -0,0 +1,131 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"math/big"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestNumber(t *testing.T) {
+	type testNumber struct {
+		Amount   jsonutil.Number `json:"amount"`
+		ID       jsonutil.Number `json:"id"`
+		Optional jsonutil.Number `json:"optional,omitzero"`
+	}
+
+	const in = `{"amount":0.10,"id":"9007199254740993","optional":1e400}`
+
+	var v testNumber
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v.Amount != "0.10" || v.ID != "9007199254740993" || v.Optional != "1e400" {
+		t.Fatalf("unexpected numbers: %+v", v)
+	}
+
+	t.Run("passthrough", func(t *testing.T) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"amount":0.10,"id":9007199254740993,"optional":1e400}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		b, err := json.Marshal(testNumber{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"amount":0,"id":0}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var n jsonutil.Number
+		if i, err := n.Int64(); err != nil || i != 0 {
+			t.Fatalf("want: 0, got: %d (%v)", i, err)
+		}
+
+		if f, err := n.Float64(); err != nil || f != 0 {
+			t.Fatalf("want: 0, got: %v (%v)", f, err)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		n := jsonutil.Number("1")
+		if err := json.Unmarshal([]byte(`null`), &n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !n.IsZero() {
+			t.Fatalf("expected zero, got: %s", n)
+		}
+	})
+
+	t.Run("conversions", func(t *testing.T) {
+		if id, err := v.ID.Int64(); err != nil || id != 9007199254740993 {
+			t.Fatalf("want: 9007199254740993, got: %d (%v)", id, err)
+		}
+
+		if f, err := v.Amount.Float64(); err != nil || f != 0.1 {
+			t.Fatalf("want: 0.1, got: %v (%v)", f, err)
+		}
+
+		if r, err := v.Amount.Rat(); err != nil || r.Cmp(big.NewRat(1, 10)) != 0 {
+			t.Fatalf("want: 1/10, got: %v (%v)", r, err)
+		}
+
+		if v.Amount.String() != "0.10" {
+			t.Fatalf("want: 0.10, got: %s", v.Amount)
+		}
+	})
+
+	for _, tc := range []struct {
+		a, b jsonutil.Number
+		want int
+	}{
+		{"1.50", "15e-1", 0},
+		{"0.1", "0.10000000000000001", -1},
+		{"9007199254740993", "9007199254740992", 1},
+		{"", "-0", 0},
+	} {
+		t.Run("compare "+tc.a.String()+" "+tc.b.String(), func(t *testing.T) {
+			if got, err := tc.a.Cmp(tc.b); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if got != tc.want {
+				t.Fatalf("want: %d, got: %d", tc.want, got)
+			}
+		})
+	}
+
+	for _, n := range []jsonutil.Number{"01", " 1", "1.", "abc", "NaN"} {
+		t.Run("invalid "+n.String(), func(t *testing.T) {
+			if _, err := json.Marshal(n); err == nil {
+				t.Fatalf("expected error")
+			}
+
+			if _, err := n.Rat(); err == nil {
+				t.Fatalf("expected error")
+			}
+
+			if _, err := n.Cmp("1"); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+
+	for _, in := range []string{`"01"`, `"abc"`, `""`, `true`, `{}`} {
+		t.Run("unmarshal "+in, func(t *testing.T) {
+			var n jsonutil.Number
+			if err := json.Unmarshal([]byte(in), &n); err == nil {
+				t.Fatalf("expected error, got: %s", n)
+			}
+		})
+	}
+}
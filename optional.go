@@ -0,0 +1,191 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"reflect"
+)
+
+type optionalState uint8
+
+const (
+	optionalUnset optionalState = iota
+	optionalNull
+	optionalValue
+)
+
+// Optional is a value that is either unset, null or set to a value of type T.
+// It distinguishes an absent JSON object member from an explicit null, e.g. for partial updates:
+//
+//	type UserPatch struct {
+//		Name  jsonutil.Optional[string] `json:"name,omitzero"`
+//		Email jsonutil.Optional[string] `json:"email,omitzero"`
+//	}
+//
+// The zero value is unset. With omitzero, an unset Optional is omitted when marshaling, a null one is
+// marshaled as null. The value is marshaled and unmarshaled with the options in use, so custom
+// marshalers for T, e.g. from Options, apply to it. See ApplyOptionals to apply a patch to a struct.
+type Optional[T any] struct {
+	value T
+	state optionalState
+}
+
+// Some returns an Optional that is set to v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, state: optionalValue}
+}
+
+// Null returns an Optional that is null.
+func Null[T any]() Optional[T] {
+	return Optional[T]{state: optionalNull}
+}
+
+// IsSet reports whether the Optional is null or has a value.
+func (o Optional[T]) IsSet() bool { return o.state != optionalUnset }
+
+// IsNull reports whether the Optional is null.
+func (o Optional[T]) IsNull() bool { return o.state == optionalNull }
+
+// IsZero reports whether the Optional is unset.
+func (o Optional[T]) IsZero() bool { return o.state == optionalUnset }
+
+// Get returns the value and whether the Optional has one.
+func (o Optional[T]) Get() (T, bool) { return o.value, o.state == optionalValue }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (o Optional[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if o.state != optionalValue {
+		return enc.WriteToken(jsontext.Null)
+	}
+
+	return json.MarshalEncode(enc, o.value)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (o *Optional[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.PeekKind() == jsontext.KindNull {
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+
+		*o = Null[T]()
+		return nil
+	}
+
+	var v T
+	if err := json.UnmarshalDecode(dec, &v); err != nil {
+		return err
+	}
+
+	*o = Some(v)
+	return nil
+}
+
+// reflectValue returns the state of the Optional and its value as a reflect.Value.
+func (o Optional[T]) reflectValue() (optionalState, reflect.Value) {
+	return o.state, reflect.ValueOf(&o.value).Elem()
+}
+
+type optional interface {
+	reflectValue() (optionalState, reflect.Value)
+}
+
+var optionalType = reflect.TypeFor[optional]()
+
+// ApplyOptionals applies a patch struct with Optional fields to the target struct.
+// Each set Optional field of the patch is applied to the target field with the same name:
+// a null one sets it to its zero value, e.g. nil for pointers, one with a value sets it to the value.
+// Values are assigned to pointer fields by pointing to a copy and converted if the types differ
+// but are of the same kind and convertible, e.g. for Optional[jsonutil.UnixTime] and a time.Time field.
+// If the target field is an Optional itself, it is replaced by the set patch field.
+// Struct fields of the patch that are not Optional are applied recursively to the struct target field.
+func ApplyOptionals[T, P any](target *T, patch P) error {
+	if target == nil {
+		return fmt.Errorf("nil target")
+	}
+
+	return applyOptionals(reflect.ValueOf(target).Elem(), reflect.ValueOf(patch))
+}
+
+func applyOptionals(target, patch reflect.Value) error {
+	if target.Kind() != reflect.Struct || patch.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot apply %s to %s: both must be structs", patch.Type(), target.Type())
+	}
+
+	for f := range patch.Type().Fields() {
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && !f.Type.Implements(optionalType) {
+			// the fields of an embedded patch struct are applied to the target itself
+			if err := applyOptionals(target, patch.Field(f.Index[0])); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		pv := patch.Field(f.Index[0])
+
+		tf, ok := target.Type().FieldByName(f.Name)
+		if !ok || !tf.IsExported() {
+			return fmt.Errorf("patch field %s has no corresponding field in %s", f.Name, target.Type())
+		}
+
+		tv, err := target.FieldByIndexErr(tf.Index)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+
+		if !f.Type.Implements(optionalType) {
+			if f.Type.Kind() != reflect.Struct {
+				return fmt.Errorf("patch field %s is of type %s, not an Optional or struct", f.Name, f.Type)
+			}
+
+			if err := applyOptionals(tv, pv); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+
+			continue
+		}
+
+		state, v := pv.Interface().(optional).reflectValue()
+		if state == optionalUnset {
+			continue
+		}
+
+		if err := applyOptional(tv, pv, state, v); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyOptional applies the set Optional o with the given state and value to the target field.
+func applyOptional(target, o reflect.Value, state optionalState, v reflect.Value) error {
+	switch t := target.Type(); {
+	case o.Type().AssignableTo(t):
+		target.Set(o)
+	case state == optionalNull:
+		target.SetZero()
+	case v.Type().AssignableTo(t):
+		target.Set(v)
+	case t.Kind() == reflect.Pointer && v.Type().AssignableTo(t.Elem()):
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		target.Set(p)
+	case v.Kind() == t.Kind() && v.Type().ConvertibleTo(t):
+		target.Set(v.Convert(t))
+	case t.Kind() == reflect.Pointer && v.Kind() == t.Elem().Kind() && v.Type().ConvertibleTo(t.Elem()):
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v.Convert(t.Elem()))
+		target.Set(p)
+	default:
+		return fmt.Errorf("cannot assign %s to %s", v.Type(), t)
+	}
+
+	return nil
+}
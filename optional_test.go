@@ -0,0 +1,198 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testUserPatch struct {
+	Name     jsonutil.Optional[string]            `json:"name,omitzero"`
+	Nickname jsonutil.Optional[string]            `json:"nickname,omitzero"`
+	Age      jsonutil.Optional[int]               `json:"age,omitzero"`
+	Birthday jsonutil.Optional[jsonutil.ISODate]  `json:"birthday,omitzero"`
+	Login    jsonutil.Optional[time.Time]         `json:"login,omitzero"`
+	Tags     jsonutil.Optional[[]string]          `json:"tags,omitzero"`
+	Address  testAddressPatch                     `json:"address,omitzero"`
+	Note     jsonutil.Optional[jsonutil.UnixTime] `json:"note,omitzero"`
+}
+
+type testAddressPatch struct {
+	City jsonutil.Optional[string] `json:"city,omitzero"`
+}
+
+type testUser struct {
+	Name     string
+	Nickname *string
+	Age      int
+	Birthday civil.Date
+	Login    time.Time
+	Tags     []string
+	Address  struct{ City, Street string }
+	Note     jsonutil.Optional[jsonutil.UnixTime]
+}
+
+func TestOptional(t *testing.T) {
+	t.Run("states", func(t *testing.T) {
+		var unset jsonutil.Optional[int]
+		if unset.IsSet() || unset.IsNull() || !unset.IsZero() {
+			t.Fatalf("expected unset")
+		}
+
+		if null := jsonutil.Null[int](); !null.IsSet() || !null.IsNull() || null.IsZero() {
+			t.Fatalf("expected null")
+		} else if _, ok := null.Get(); ok {
+			t.Fatalf("expected no value")
+		}
+
+		if some := jsonutil.Some(0); !some.IsSet() || some.IsNull() || some.IsZero() {
+			t.Fatalf("expected value")
+		} else if v, ok := some.Get(); !ok || v != 0 {
+			t.Fatalf("want: 0, got: %d", v)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var p testUserPatch
+		if err := json.Unmarshal([]byte(`{"name":"Alice","nickname":null,"tags":[],"address":{"city":null}}`), &p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if name, ok := p.Name.Get(); !ok || name != "Alice" {
+			t.Fatalf(`want: "Alice", got: %q`, name)
+		}
+
+		if !p.Nickname.IsNull() || p.Age.IsSet() || !p.Address.City.IsNull() {
+			t.Fatalf("unexpected states: %+v", p)
+		}
+
+		if tags, ok := p.Tags.Get(); !ok || tags == nil || len(tags) != 0 {
+			t.Fatalf("want: empty tags, got: %v", tags)
+		}
+
+		if err := json.Unmarshal([]byte(`{"age":"3"}`), &p); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		b, err := json.Marshal(testUserPatch{
+			Name:     jsonutil.Some("Bob"),
+			Nickname: jsonutil.Null[string](),
+			Age:      jsonutil.Some(0),
+			Birthday: jsonutil.Some(jsonutil.ISODate{Year: 2000, Month: time.March, Day: 4}),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"name":"Bob","nickname":null,"age":0,"birthday":"2000-03-04"}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("custom codecs", func(t *testing.T) {
+		opts := jsonutil.Options(jsonutil.Config{Time: jsonutil.TimeUnixSeconds})
+		ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		b, err := json.Marshal(testUserPatch{Login: jsonutil.Some(ts)}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"login":1704164645}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var p testUserPatch
+		if err := json.Unmarshal(b, &p, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if login, ok := p.Login.Get(); !ok || !login.Equal(ts) {
+			t.Fatalf("want: %s, got: %s", ts, login)
+		}
+	})
+}
+
+func TestApplyOptionals(t *testing.T) {
+	nickname := "Al"
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	user := testUser{Name: "Alice", Nickname: &nickname, Age: 30, Tags: []string{"a"}}
+	user.Address.City, user.Address.Street = "Berlin", "Main St"
+
+	var p testUserPatch
+	if err := json.Unmarshal([]byte(`{"name":"Alicia","nickname":null,"birthday":"2000-03-04",`+
+		`"login":"2024-01-02T03:04:05Z","address":{"city":"Paris"},"note":1704164645}`), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := jsonutil.ApplyOptionals(&user, p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.Name != "Alicia" || user.Nickname != nil || user.Age != 30 || !user.Login.Equal(ts) ||
+		user.Birthday != (civil.Date{Year: 2000, Month: time.March, Day: 4}) ||
+		len(user.Tags) != 1 || user.Address.City != "Paris" || user.Address.Street != "Main St" {
+		t.Fatalf("unexpected result: %+v", user)
+	}
+
+	if note, ok := user.Note.Get(); !ok || !time.Time(note).Equal(ts) {
+		t.Fatalf("want: %s, got: %v", ts, user.Note)
+	}
+
+	t.Run("pointer", func(t *testing.T) {
+		if err := jsonutil.ApplyOptionals(&user, struct{ Nickname jsonutil.Optional[string] }{
+			jsonutil.Some("Ally"),
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if user.Nickname == nil || *user.Nickname != "Ally" {
+			t.Fatalf(`want: "Ally", got: %v`, user.Nickname)
+		}
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		type base struct{ Age jsonutil.Optional[int] }
+
+		if err := jsonutil.ApplyOptionals(&user, struct{ base }{base{jsonutil.Some(31)}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if user.Age != 31 {
+			t.Fatalf("want: 31, got: %d", user.Age)
+		}
+	})
+
+	for _, tc := range []struct {
+		name  string
+		apply func() error
+		msg   string
+	}{
+		{"nil target", func() error { return jsonutil.ApplyOptionals[testUser](nil, p) }, "nil target"},
+		{"not a struct", func() error { return jsonutil.ApplyOptionals(&user, 3) }, "must be structs"},
+		{"unknown field", func() error {
+			return jsonutil.ApplyOptionals(&user, struct{ Email jsonutil.Optional[string] }{})
+		}, "no corresponding field"},
+		{"not optional", func() error {
+			return jsonutil.ApplyOptionals(&user, struct{ Name string }{"x"})
+		}, "not an Optional"},
+		{"incompatible", func() error {
+			return jsonutil.ApplyOptionals(&user, struct{ Name jsonutil.Optional[int] }{jsonutil.Some(65)})
+		}, "cannot assign int to string"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.apply(); err == nil {
+				t.Fatalf("expected error")
+			} else if !strings.Contains(err.Error(), tc.msg) {
+				t.Fatalf("expected error to contain %q, got: %v", tc.msg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package jsonutil
+
+import (
+	"encoding/json/v2"
+	"fmt"
+)
+
+// TimeFormat is the JSON representation of time.Time values.
+type TimeFormat int
+
+const (
+	// TimeDefault keeps the default representation of encoding/json/v2, an RFC 3339 string.
+	TimeDefault TimeFormat = iota
+	// TimeUnixSeconds uses integers representing unix time, see TimeMarshalIntUnix and TimeUnmarshalIntUnix.
+	TimeUnixSeconds
+	// TimeUnixSecondsOrString marshals integers representing unix time and unmarshals
+	// either those or strings, see TimeUnmarshalStringOrIntUnix.
+	TimeUnixSecondsOrString
+	// TimeUnixMilliseconds uses integers representing unix time in milliseconds,
+	// see TimeMarshalIntUnixMilli and TimeUnmarshalIntUnixMilli.
+	TimeUnixMilliseconds
+)
+
+// DateFormat is the JSON representation of civil.Date values.
+type DateFormat int
+
+const (
+	// DateDefault keeps the default representation of encoding/json/v2, a string like "2006-01-02".
+	DateDefault DateFormat = iota
+	// DateUnixSeconds uses integers representing unix time, see DateMarshalIntUnix and DateUnmarshalIntUnix.
+	DateUnixSeconds
+)
+
+// DurationFormat is the JSON representation of time.Duration values.
+type DurationFormat int
+
+const (
+	// DurationDefault keeps the default representation of encoding/json/v2.
+	DurationDefault DurationFormat = iota
+	// DurationSeconds uses integers representing seconds, see DurationMarshalIntSeconds and DurationUnmarshalIntSeconds.
+	DurationSeconds
+	// DurationString uses strings like "1h30m", see DurationMarshalString and DurationUnmarshalString.
+	DurationString
+	// DurationISO8601 uses ISO 8601 strings like "PT1H30M", see DurationMarshalISO8601 and DurationUnmarshalISO8601.
+	DurationISO8601
+)
+
+// URLFormat is the JSON representation of url.URL values.
+type URLFormat int
+
+const (
+	// URLDefault keeps the default representation of encoding/json/v2, an object with the fields of url.URL.
+	URLDefault URLFormat = iota
+	// URLString uses strings, see URLMarshal and URLUnmarshal.
+	URLString
+)
+
+// HeaderFormat is the JSON representation of http.Header values.
+type HeaderFormat int
+
+const (
+	// HeaderMultiValue keeps the default representation of encoding/json/v2, an object of arrays of strings.
+	HeaderMultiValue HeaderFormat = iota
+	// HeaderSingleValue uses an object of strings, see HTTPHeaderMarshal and HTTPHeaderUnmarshal.
+	HeaderSingleValue
+)
+
+// Config selects the JSON representation of the types this package supports.
+// The zero value keeps the default representations of encoding/json/v2.
+type Config struct {
+	Time     TimeFormat
+	Date     DateFormat
+	Duration DurationFormat
+	URL      URLFormat
+	Header   HeaderFormat
+	// SortMapKeys marshals the members of all maps sorted by key, like OrderedMapMarshal.
+	SortMapKeys bool
+	// FieldFormats enables `jsonutil` struct tags that select the format of single fields, see FieldFormats.
+	FieldFormats bool
+}
+
+// Options returns the JSON options that marshal and unmarshal values as configured,
+// so that the wire format can be defined in one place, e.g.
+//
+//	var jsonOpts = jsonutil.Options(jsonutil.Config{Time: jsonutil.TimeUnixSeconds, URL: jsonutil.URLString})
+//
+// Since later marshalers replace earlier ones when options are joined, additional custom marshalers
+// should not be passed as separate options but combined with json.JoinMarshalers instead.
+// It panics if the configuration contains an unknown format.
+func Options(c Config) json.Options {
+	var (
+		marshalers   []*json.Marshalers
+		unmarshalers []*json.Unmarshalers
+	)
+
+	if c.FieldFormats {
+		marshalers = append(marshalers, json.MarshalToFunc(marshalFieldFormats))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(unmarshalFieldFormats))
+	}
+
+	switch c.Time {
+	case TimeDefault:
+	case TimeUnixSeconds:
+		marshalers = append(marshalers, json.MarshalToFunc(TimeMarshalIntUnix))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(TimeUnmarshalIntUnix))
+	case TimeUnixSecondsOrString:
+		marshalers = append(marshalers, json.MarshalToFunc(TimeMarshalIntUnix))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(TimeUnmarshalStringOrIntUnix))
+	case TimeUnixMilliseconds:
+		marshalers = append(marshalers, json.MarshalToFunc(TimeMarshalIntUnixMilli))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(TimeUnmarshalIntUnixMilli))
+	default:
+		panic(fmt.Sprintf("jsonutil: unknown TimeFormat %d", c.Time))
+	}
+
+	switch c.Date {
+	case DateDefault:
+	case DateUnixSeconds:
+		marshalers = append(marshalers, json.MarshalToFunc(DateMarshalIntUnix))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(DateUnmarshalIntUnix))
+	default:
+		panic(fmt.Sprintf("jsonutil: unknown DateFormat %d", c.Date))
+	}
+
+	switch c.Duration {
+	case DurationDefault:
+	case DurationSeconds:
+		marshalers = append(marshalers, json.MarshalToFunc(DurationMarshalIntSeconds))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(DurationUnmarshalIntSeconds))
+	case DurationString:
+		marshalers = append(marshalers, json.MarshalToFunc(DurationMarshalString))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(DurationUnmarshalString))
+	case DurationISO8601:
+		marshalers = append(marshalers, json.MarshalToFunc(DurationMarshalISO8601))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(DurationUnmarshalISO8601))
+	default:
+		panic(fmt.Sprintf("jsonutil: unknown DurationFormat %d", c.Duration))
+	}
+
+	switch c.URL {
+	case URLDefault:
+	case URLString:
+		marshalers = append(marshalers, json.MarshalToFunc(URLMarshal))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(URLUnmarshal))
+	default:
+		panic(fmt.Sprintf("jsonutil: unknown URLFormat %d", c.URL))
+	}
+
+	switch c.Header {
+	case HeaderMultiValue:
+	case HeaderSingleValue:
+		marshalers = append(marshalers, json.MarshalToFunc(HTTPHeaderMarshal))
+		unmarshalers = append(unmarshalers, json.UnmarshalFromFunc(HTTPHeaderUnmarshal))
+	default:
+		panic(fmt.Sprintf("jsonutil: unknown HeaderFormat %d", c.Header))
+	}
+
+	var opts []json.Options
+	if len(marshalers) > 0 {
+		opts = append(opts,
+			json.WithMarshalers(json.JoinMarshalers(marshalers...)),
+			json.WithUnmarshalers(json.JoinUnmarshalers(unmarshalers...)),
+		)
+	}
+
+	if c.SortMapKeys {
+		opts = append(opts, json.Deterministic(true))
+	}
+
+	return json.JoinOptions(opts...)
+}
@@ -0,0 +1,140 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestOptions(t *testing.T) {
+	type wire struct {
+		Time     time.Time      `json:"time"`
+		Date     civil.Date     `json:"date"`
+		Duration time.Duration  `json:"duration"`
+		URL      url.URL        `json:"url"`
+		Header   http.Header    `json:"header"`
+		Map      map[string]int `json:"map"`
+	}
+
+	v := wire{
+		Time:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Date:     civil.Date{Year: 2024, Month: time.January, Day: 2},
+		Duration: 90 * time.Minute,
+		URL:      url.URL{Scheme: "https", Host: "example.com"},
+		Header:   http.Header{"Accept": {"text/plain"}},
+		Map:      map[string]int{"b": 2, "a": 1},
+	}
+
+	for _, tc := range []struct {
+		name string
+		c    jsonutil.Config
+		want string
+	}{
+		{
+			"unix seconds",
+			jsonutil.Config{
+				Time:        jsonutil.TimeUnixSeconds,
+				Date:        jsonutil.DateUnixSeconds,
+				Duration:    jsonutil.DurationSeconds,
+				URL:         jsonutil.URLString,
+				Header:      jsonutil.HeaderSingleValue,
+				SortMapKeys: true,
+			},
+			`{"time":1704164645,"date":1704153600,"duration":5400,"url":"https://example.com",` +
+				`"header":{"Accept":"text/plain"},"map":{"a":1,"b":2}}`,
+		},
+		{
+			"strings",
+			jsonutil.Config{
+				Time:        jsonutil.TimeUnixSecondsOrString,
+				Duration:    jsonutil.DurationString,
+				URL:         jsonutil.URLString,
+				SortMapKeys: true,
+			},
+			`{"time":1704164645,"date":"2024-01-02","duration":"1h30m0s","url":"https://example.com",` +
+				`"header":{"Accept":["text/plain"]},"map":{"a":1,"b":2}}`,
+		},
+		{
+			"ISO 8601",
+			jsonutil.Config{Duration: jsonutil.DurationISO8601, URL: jsonutil.URLString, SortMapKeys: true},
+			`{"time":"2024-01-02T03:04:05Z","date":"2024-01-02","duration":"PT1H30M","url":"https://example.com",` +
+				`"header":{"Accept":["text/plain"]},"map":{"a":1,"b":2}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := jsonutil.Options(tc.c)
+
+			b, err := json.Marshal(v, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, b)
+			}
+
+			var out wire
+			if err := json.Unmarshal(b, &out, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !out.Time.Equal(v.Time) || out.Date != v.Date || out.Duration != v.Duration ||
+				out.URL != v.URL || out.Header.Get("Accept") != "text/plain" || len(out.Map) != 2 {
+				t.Fatalf("want: %+v, got: %+v", v, out)
+			}
+		})
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		b, err := json.Marshal(v.Time, jsonutil.Options(jsonutil.Config{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `"2024-01-02T03:04:05Z"`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("unix milliseconds", func(t *testing.T) {
+		b, err := json.Marshal(v.Time, jsonutil.Options(jsonutil.Config{Time: jsonutil.TimeUnixMilliseconds}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `1704164645000`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("string or unix time", func(t *testing.T) {
+		var out time.Time
+		if err := json.Unmarshal([]byte(`"2024-01-02T03:04:05Z"`), &out,
+			jsonutil.Options(jsonutil.Config{Time: jsonutil.TimeUnixSecondsOrString})); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !out.Equal(v.Time) {
+			t.Fatalf("want: %s, got: %s", v.Time, out)
+		}
+	})
+
+	for _, c := range []jsonutil.Config{
+		{Time: -1}, {Date: -1}, {Duration: -1}, {URL: -1}, {Header: -1},
+	} {
+		t.Run("unknown format", func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic for %+v", c)
+				}
+			}()
+
+			jsonutil.Options(c)
+		})
+	}
+}
@@ -18,7 +18,7 @@ func OrderedMapMarshal[M ~map[K]V, K cmp.Ordered, V any](enc *jsontext.Encoder,
 		return err
 	}
 
-	for _, key := range slices.Sorted(maps.Keys(m)) {
+	for _, key := range sortedKeys(m) {
 		if err := json.MarshalEncode(enc, key); err != nil {
 			return err
 		}
@@ -30,3 +30,8 @@ func OrderedMapMarshal[M ~map[K]V, K cmp.Ordered, V any](enc *jsontext.Encoder,
 
 	return enc.WriteToken(jsontext.EndObject)
 }
+
+// sortedKeys returns the keys of the map in ascending order.
+func sortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+	return slices.Sorted(maps.Keys(m))
+}
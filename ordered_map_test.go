@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
 )
 
 type orderedMap map[string]int
@@ -76,3 +77,9 @@ func TestMap(t *testing.T) {
 		})
 	}
 }
+
+func FuzzOrderedMap(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, jsontest.Codec[map[string]int]{
+		Marshal: jsonutil.OrderedMapMarshal[map[string]int],
+	}, `{"b":2,"a":1}`, `{}`, `null`, `{"":0,"\u00e9":-1}`)
+}
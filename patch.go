@@ -0,0 +1,264 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrTestFailed is returned when a "test" operation of a JSON Patch fails.
+var ErrTestFailed = errors.New("test failed")
+
+// Patch is a JSON Patch document as defined in RFC 6902.
+type Patch []PatchOperation
+
+// PatchOperation is a single operation of a JSON Patch.
+type PatchOperation struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" or "test".
+	Op string `json:"op"`
+	// Path references the location the operation is performed on.
+	Path jsontext.Pointer `json:"path"`
+	// From references the source location of "move" and "copy" operations.
+	From jsontext.Pointer `json:"from,omitzero"`
+	// Value is the value of "add", "replace" and "test" operations.
+	Value jsontext.Value `json:"value,omitzero"`
+}
+
+// patchOperationJSON is the JSON representation of a PatchOperation.
+// Its JSON Pointers are pointers, so that a missing one can be told apart from the empty one.
+type patchOperationJSON struct {
+	Op    string            `json:"op"`
+	Path  *jsontext.Pointer `json:"path"`
+	From  *jsontext.Pointer `json:"from,omitzero"`
+	Value jsontext.Value    `json:"value,omitzero"`
+}
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (op PatchOperation) MarshalJSONTo(enc *jsontext.Encoder) error {
+	v := patchOperationJSON{Op: op.Op, Path: &op.Path, Value: op.Value}
+	if op.Op == "move" || op.Op == "copy" {
+		v.From = &op.From
+	}
+
+	return json.MarshalEncode(enc, v)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (op *PatchOperation) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var v patchOperationJSON
+	if err := json.UnmarshalDecode(dec, &v); err != nil {
+		return err
+	}
+
+	if v.Path == nil {
+		return errors.New(`missing "path"`)
+	}
+
+	if v.From == nil && (v.Op == "move" || v.Op == "copy") {
+		return errors.New(`missing "from"`)
+	}
+
+	*op = PatchOperation{Op: v.Op, Path: *v.Path, Value: v.Value}
+	if v.From != nil {
+		op.From = *v.From
+	}
+
+	return nil
+}
+
+// ApplyPatch applies a JSON Patch document, e.g. a request body of type application/json-patch+json, to doc.
+func ApplyPatch(doc, patch jsontext.Value) (jsontext.Value, error) {
+	var p Patch
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+
+	return p.Apply(doc)
+}
+
+// Apply applies the operations of the patch to doc in order and returns the result.
+// Either all operations are applied or, if one fails, none is and an error is returned.
+// The document itself is never modified.
+func (p Patch) Apply(doc jsontext.Value) (jsontext.Value, error) {
+	for i, op := range p {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %q): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func (op PatchOperation) apply(doc jsontext.Value) (jsontext.Value, error) {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, errors.New(`missing "value"`)
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return AddPointer(doc, op.Path, op.Value)
+	case "remove":
+		return RemovePointer(doc, op.Path)
+	case "replace":
+		if _, err := GetPointer(doc, op.Path); err != nil {
+			return nil, err
+		}
+
+		return SetPointer(doc, op.Path, op.Value)
+	case "move":
+		if op.Path != op.From && op.From.Contains(op.Path) {
+			return nil, fmt.Errorf("cannot move %q into one of its children", op.From)
+		}
+
+		v, err := GetPointer(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc, err = RemovePointer(doc, op.From); err != nil {
+			return nil, err
+		}
+
+		return AddPointer(doc, op.Path, v)
+	case "copy":
+		v, err := GetPointer(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		return AddPointer(doc, op.Path, v)
+	case "test":
+		v, err := GetPointer(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equalValues(v, op.Value) {
+			return nil, fmt.Errorf("%w: want %s, got %s", ErrTestFailed, op.Value, v)
+		}
+
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// CreatePatch returns a JSON Patch that transforms a into b.
+// Objects are compared member by member and arrays element by element, using the minimal number
+// of additions, removals and replacements to transform one array into the other.
+func CreatePatch(a, b jsontext.Value) (Patch, error) {
+	if !a.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", a)
+	}
+
+	if !b.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", b)
+	}
+
+	p := Patch{}
+	if err := p.diff("", a, b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Patch) diff(ptr jsontext.Pointer, a, b jsontext.Value) error {
+	if equalValues(a, b) {
+		return nil
+	}
+
+	switch kind := a.Kind(); {
+	case kind == jsontext.KindBeginObject && b.Kind() == kind:
+		return p.diffObjects(ptr, a, b)
+	case kind == jsontext.KindBeginArray && b.Kind() == kind:
+		return p.diffArrays(ptr, a, b)
+	default:
+		*p = append(*p, PatchOperation{Op: "replace", Path: ptr, Value: b.Clone()})
+		return nil
+	}
+}
+
+func (p *Patch) diffObjects(ptr jsontext.Pointer, a, b jsontext.Value) error {
+	membersA, err := readMembers(a)
+	if err != nil {
+		return err
+	}
+
+	membersB, err := readMembers(b)
+	if err != nil {
+		return err
+	}
+
+	valuesB := make(map[string]jsontext.Value, len(membersB))
+	for _, m := range membersB {
+		valuesB[m.name] = m.value
+	}
+
+	namesA := make(map[string]bool, len(membersA))
+	for _, m := range membersA {
+		namesA[m.name] = true
+
+		vb, ok := valuesB[m.name]
+		if !ok {
+			*p = append(*p, PatchOperation{Op: "remove", Path: ptr.AppendToken(m.name)})
+			continue
+		}
+
+		if err := p.diff(ptr.AppendToken(m.name), m.value, vb); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range membersB {
+		if !namesA[m.name] {
+			*p = append(*p, PatchOperation{Op: "add", Path: ptr.AppendToken(m.name), Value: m.value})
+		}
+	}
+
+	return nil
+}
+
+func (p *Patch) diffArrays(ptr jsontext.Pointer, a, b jsontext.Value) error {
+	var elemsA, elemsB []jsontext.Value
+	if err := json.Unmarshal(a, &elemsA); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, &elemsB); err != nil {
+		return err
+	}
+
+	keysA, err := semanticKeys(elemsA)
+	if err != nil {
+		return err
+	}
+
+	keysB, err := semanticKeys(elemsB)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range alignSequences(len(elemsA), len(elemsB), func(i, j int) bool {
+		return keysA[i] == keysB[j]
+	}) {
+		switch e.op {
+		case editSubstitute:
+			if err := p.diff(ptr.AppendToken(strconv.Itoa(e.i)), elemsA[e.i], elemsB[e.j]); err != nil {
+				return err
+			}
+		case editDelete:
+			*p = append(*p, PatchOperation{Op: "remove", Path: ptr.AppendToken(strconv.Itoa(e.i))})
+		case editInsert:
+			*p = append(*p, PatchOperation{Op: "add", Path: ptr.AppendToken(strconv.Itoa(e.i)), Value: elemsB[e.j]})
+		}
+	}
+
+	return nil
+}
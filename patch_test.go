@@ -0,0 +1,366 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+// equalJSON reports whether two JSON values are equal, ignoring the order of object members.
+func equalJSON(t *testing.T, a, b jsontext.Value) bool {
+	t.Helper()
+
+	a, b = a.Clone(), b.Clone()
+	if err := a.Canonicalize(); err != nil {
+		t.Fatalf("invalid JSON %s: %v", a, err)
+	}
+
+	if err := b.Canonicalize(); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b, err)
+	}
+
+	return string(a) == string(b)
+}
+
+func TestApplyPatch(t *testing.T) {
+	// examples from RFC 6902, appendix A
+	for _, tc := range []struct {
+		name, doc, patch, want string
+	}{
+		{
+			"A.1 adding an object member",
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`,
+			`{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			"A.2 adding an array element",
+			`{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			`{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			"A.3 removing an object member",
+			`{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`,
+			`{"foo":"bar"}`,
+		},
+		{
+			"A.4 removing an array element",
+			`{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`,
+			`{"foo":["bar","baz"]}`,
+		},
+		{
+			"A.5 replacing a value",
+			`{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`,
+			`{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			"A.6 moving a value",
+			`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			"A.7 moving an array element",
+			`{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			"A.8 testing a value: success",
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			"A.10 adding a nested member object",
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			`{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			"A.11 ignoring unrecognized elements",
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			`{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			"A.14 ~ escape ordering",
+			`{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10}]`,
+			`{"/":9,"~1":10}`,
+		},
+		{
+			"A.16 adding an array value",
+			`{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			`{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			"copy",
+			`{"a":{"b":1}}`,
+			`[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			`{"a":{"b":1},"c":{"b":2}}`,
+		},
+		{
+			"replace document",
+			`{"a":1}`,
+			`[{"op":"replace","path":"","value":[1]}]`,
+			`[1]`,
+		},
+		{
+			"copy document",
+			`{"a":1}`,
+			`[{"op":"copy","from":"","path":"/b"}]`,
+			`{"a":1,"b":{"a":1}}`,
+		},
+		{
+			"test numbers by value and objects regardless of order",
+			`{"a":{"x":1.0,"y":[true,null]}}`,
+			`[{"op":"test","path":"/a","value":{"y":[true,null],"x":1}}]`,
+			`{"a":{"x":1.0,"y":[true,null]}}`,
+		},
+		{
+			"move to same location",
+			`{"a":1}`,
+			`[{"op":"move","from":"/a","path":"/a"}]`,
+			`{"a":1}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := jsonutil.ApplyPatch(jsontext.Value(tc.doc), jsontext.Value(tc.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !equalJSON(t, got, jsontext.Value(tc.want)) {
+				t.Fatalf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name, doc, patch string
+		check            func(error) bool
+	}{
+		{
+			"A.9 testing a value: error",
+			`{"baz":"qux"}`,
+			`[{"op":"test","path":"/baz","value":"bar"}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrTestFailed) },
+		},
+		{
+			"A.12 adding to a nonexistent target",
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) },
+		},
+		{
+			"A.13 invalid JSON patch document",
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux","op":"remove"}]`,
+			func(err error) bool {
+				errSyn := &jsontext.SyntacticError{}
+				return errors.As(err, &errSyn)
+			},
+		},
+		{
+			"A.15 comparing strings and numbers",
+			`{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":"10"}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrTestFailed) },
+		},
+		{
+			"testing a large integer",
+			`{"id":9007199254740993}`,
+			`[{"op":"test","path":"/id","value":9007199254740992}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrTestFailed) },
+		},
+		{
+			"atomic",
+			`{"a":1}`,
+			`[{"op":"remove","path":"/a"},{"op":"remove","path":"/a"}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) },
+		},
+		{
+			"replace nonexistent",
+			`{"a":[1]}`,
+			`[{"op":"replace","path":"/a/1","value":2}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) },
+		},
+		{
+			"move into child",
+			`{"a":{"b":1}}`,
+			`[{"op":"move","from":"/a","path":"/a/c"}]`,
+			func(err error) bool { return err != nil },
+		},
+		{
+			"move nonexistent",
+			`{"a":{"b":1}}`,
+			`[{"op":"move","from":"/b","path":"/c"}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) },
+		},
+		{
+			"copy nonexistent",
+			`{"a":{"b":1}}`,
+			`[{"op":"copy","from":"/b","path":"/c"}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) },
+		},
+		{
+			"test nonexistent",
+			`{"a":{"b":1}}`,
+			`[{"op":"test","path":"/b","value":1}]`,
+			func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) },
+		},
+		{"unknown op", `{}`, `[{"op":"frobnicate","path":"/a"}]`, func(err error) bool { return err != nil }},
+		{"missing path", `{}`, `[{"op":"add","value":1}]`, func(err error) bool { return err != nil }},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, func(err error) bool { return err != nil }},
+		{"missing from", `{}`, `[{"op":"copy","path":"/a"}]`, func(err error) bool { return err != nil }},
+		{"invalid path", `{}`, `[{"op":"add","path":"a","value":1}]`, func(err error) bool { return err != nil }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := jsonutil.ApplyPatch(jsontext.Value(tc.doc), jsontext.Value(tc.patch))
+			if !tc.check(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != nil {
+				t.Fatalf("expected no result, got: %s", got)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		p := jsonutil.Patch{{Op: "copy", Path: "/b"}, {Op: "remove", Path: "/a~1b"}}
+
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `[{"op":"copy","path":"/b","from":""},{"op":"remove","path":"/a~1b"}]`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var out jsonutil.Patch
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(out, p) {
+			t.Fatalf("want: %v, got: %v", p, out)
+		}
+	})
+}
+
+func TestCreatePatch(t *testing.T) {
+	for _, tc := range []struct {
+		name, a, b, want string
+	}{
+		{"equal", `{"a":1,"b":[1,2]}`, `{"b":[1,2.0],"a":1}`, `[]`},
+		{"replace document", `{"a":1}`, `[1]`, `[{"op":"replace","path":"","value":[1]}]`},
+		{
+			"object members",
+			`{"a":1,"b":{"c":2,"d":3},"e":4}`,
+			`{"a":1,"b":{"c":5,"d":3},"f":6}`,
+			`[{"op":"replace","path":"/b/c","value":5},{"op":"remove","path":"/e"},{"op":"add","path":"/f","value":6}]`,
+		},
+		{
+			"array insertion",
+			`[1,2,3]`,
+			`[1,2,9,3]`,
+			`[{"op":"add","path":"/2","value":9}]`,
+		},
+		{
+			"array removal",
+			`[1,2,3,4]`,
+			`[1,4]`,
+			`[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`,
+		},
+		{
+			"array element change",
+			`[{"id":1,"name":"a"},{"id":2,"name":"b"}]`,
+			`[{"id":1,"name":"a"},{"id":2,"name":"c"}]`,
+			`[{"op":"replace","path":"/1/name","value":"c"}]`,
+		},
+		{
+			"large integers",
+			`{"id":9007199254740993,"ids":[9007199254740993,1]}`,
+			`{"id":9007199254740992,"ids":[9007199254740992,1]}`,
+			`[{"op":"replace","path":"/id","value":9007199254740992},{"op":"replace","path":"/ids/0","value":9007199254740992}]`,
+		},
+		{
+			"escaped names",
+			`{"a/b":1}`,
+			`{"a/b":2,"~":3}`,
+			`[{"op":"replace","path":"/a~1b","value":2},{"op":"add","path":"/~0","value":3}]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := jsonutil.CreatePatch(jsontext.Value(tc.a), jsontext.Value(tc.b))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(p)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want: %s, got: %s", tc.want, b)
+			}
+
+			got, err := p.Apply(jsontext.Value(tc.a))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !equalJSON(t, got, jsontext.Value(tc.b)) {
+				t.Fatalf("want: %s, got: %s", tc.b, got)
+			}
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, tc := range []struct{ a, b string }{
+			{`[1,2,3,4,5]`, `[5,4,3,2,1]`},
+			{`["a","b","c","d"]`, `["x","b","d","y","z"]`},
+			{`[]`, `[1,2,3]`},
+			{`[1,2,3]`, `[]`},
+			{`{"a":[1,{"b":[2,3]}]}`, `{"a":[{"b":[3,2]},1,4]}`},
+			{`{"a":null}`, `{"a":{}}`},
+		} {
+			p, err := jsonutil.CreatePatch(jsontext.Value(tc.a), jsontext.Value(tc.b))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := p.Apply(jsontext.Value(tc.a))
+			if err != nil {
+				t.Fatalf("%s -> %s: unexpected error: %v", tc.a, tc.b, err)
+			}
+
+			if !equalJSON(t, got, jsontext.Value(tc.b)) {
+				t.Fatalf("%s -> %s: want: %s, got: %s", tc.a, tc.b, tc.b, got)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := jsonutil.CreatePatch(jsontext.Value(`{`), jsontext.Value(`{}`)); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if _, err := jsonutil.CreatePatch(jsontext.Value(`{}`), jsontext.Value(`{`)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
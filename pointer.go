@@ -0,0 +1,324 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReadPointer advances the decoder to the value addressed by the JSON Pointer and reads it.
+// Everything before the value is skipped without being decoded, so a single value can be extracted
+// from a large document without holding the document in memory.
+// The decoder is left positioned after the value.
+func ReadPointer(dec *jsontext.Decoder, ptr jsontext.Pointer) (jsontext.Value, error) {
+	if err := seekPointer(dec, ptr); err != nil {
+		return nil, err
+	}
+
+	return dec.ReadValue()
+}
+
+// GetPointer returns the value addressed by the JSON Pointer within v.
+func GetPointer(v jsontext.Value, ptr jsontext.Pointer) (jsontext.Value, error) {
+	val, err := ReadPointer(jsontext.NewDecoder(bytes.NewReader(v)), ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return val.Clone(), nil
+}
+
+// SetPointer returns a copy of v in which the value addressed by the JSON Pointer is replaced by val.
+// If the pointer addresses a nonexistent member of an object, the member is added.
+// If it addresses the index right after the last element of an array, or "-", the value is appended.
+func SetPointer(v jsontext.Value, ptr jsontext.Pointer, val jsontext.Value) (jsontext.Value, error) {
+	if !val.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", val)
+	}
+
+	return modifyPointer(v, ptr, val, opSet)
+}
+
+// AddPointer returns a copy of v with val added as described by the "add" operation of RFC 6902.
+// It is like SetPointer, except that a value addressed by an array index is inserted before the element at that index.
+func AddPointer(v jsontext.Value, ptr jsontext.Pointer, val jsontext.Value) (jsontext.Value, error) {
+	if !val.IsValid() {
+		return nil, fmt.Errorf("invalid JSON value %q", val)
+	}
+
+	return modifyPointer(v, ptr, val, opAdd)
+}
+
+// RemovePointer returns a copy of v without the value addressed by the JSON Pointer.
+func RemovePointer(v jsontext.Value, ptr jsontext.Pointer) (jsontext.Value, error) {
+	if ptr == "" {
+		return nil, errors.New("cannot remove the whole document")
+	}
+
+	return modifyPointer(v, ptr, nil, opRemove)
+}
+
+// pointerTokens are the unescaped reference tokens of a JSON Pointer.
+type pointerTokens []string
+
+// pointerTokensOf validates the JSON Pointer and returns its reference tokens.
+func pointerTokensOf(ptr jsontext.Pointer) (pointerTokens, error) {
+	if !ptr.IsValid() {
+		return nil, fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+
+	return slices.Collect(ptr.Tokens()), nil
+}
+
+// notFound returns an error reporting that the value addressed by the tokens does not exist.
+func (p pointerTokens) notFound() error {
+	ptr := jsontext.Pointer("")
+	for _, tok := range p {
+		ptr = ptr.AppendToken(tok)
+	}
+
+	return fmt.Errorf("%w: %q", ErrPointerNotFound, ptr)
+}
+
+type pointerOp int
+
+const (
+	opSet pointerOp = iota
+	opAdd
+	opRemove
+	// opForce is like opSet, but creates missing objects along the way
+	// and replaces values that are neither objects nor arrays by objects.
+	opForce
+)
+
+func modifyPointer(v jsontext.Value, ptr jsontext.Pointer, val jsontext.Value, op pointerOp) (jsontext.Value, error) {
+	p, err := pointerTokensOf(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.modify(v, 0, val, op)
+}
+
+// modify applies the operation to v, which is the value addressed by the first depth tokens of the pointer.
+func (p pointerTokens) modify(v jsontext.Value, depth int, val jsontext.Value, op pointerOp) (jsontext.Value, error) {
+	if depth == len(p) {
+		return val.Clone(), nil
+	}
+
+	tok, last := p[depth], depth == len(p)-1
+
+	switch v.Kind() {
+	case jsontext.KindBeginObject:
+		members, err := readMembers(v)
+		if err != nil {
+			return nil, err
+		}
+
+		i := slices.IndexFunc(members, func(m member) bool { return m.name == tok })
+
+		switch {
+		case i < 0 && !last && op == opForce:
+			child, err := p.modify(nil, depth+1, val, op)
+			if err != nil {
+				return nil, err
+			}
+
+			members = append(members, member{name: tok, value: child})
+		case i < 0 && (!last || op == opRemove):
+			return nil, p.notFound()
+		case i < 0:
+			members = append(members, member{name: tok, value: val})
+		case last && op == opRemove:
+			members = slices.Delete(members, i, i+1)
+		case last:
+			members[i].value = val
+		default:
+			if members[i].value, err = p.modify(members[i].value, depth+1, val, op); err != nil {
+				return nil, err
+			}
+		}
+
+		return encodeMembers(members)
+	case jsontext.KindBeginArray:
+		var elems []jsontext.Value
+		if err := json.Unmarshal(v, &elems); err != nil {
+			return nil, err
+		}
+
+		i, err := arrayIndex(tok, len(elems))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case i > len(elems) || (i == len(elems) && (!last || op == opRemove)):
+			return nil, p.notFound()
+		case last && op == opRemove:
+			elems = slices.Delete(elems, i, i+1)
+		case last && (op == opAdd || i == len(elems)):
+			elems = slices.Insert(elems, i, val)
+		case last:
+			elems[i] = val
+		default:
+			if elems[i], err = p.modify(elems[i], depth+1, val, op); err != nil {
+				return nil, err
+			}
+		}
+
+		return json.Marshal(elems)
+	default:
+		if op == opForce {
+			return p.modify(jsontext.Value("{}"), depth, val, op)
+		}
+
+		return nil, p.notFound()
+	}
+}
+
+// arrayIndex parses a reference token as an index into an array of length n.
+// The token "-" references the index after the last element.
+func arrayIndex(tok string, n int) (int, error) {
+	if tok == "-" {
+		return n, nil
+	}
+
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') || strings.TrimLeft(tok, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPointerNotFound, tok)
+	}
+
+	i, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPointerNotFound, tok)
+	}
+
+	return i, nil
+}
+
+// GetPointerAny returns the value addressed by the JSON Pointer within a tree of decoded JSON values,
+// i.e. map[string]any for objects and []any for arrays.
+func GetPointerAny(tree any, ptr jsontext.Pointer) (any, error) {
+	p, err := pointerTokensOf(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tok := range p {
+		switch node := tree.(type) {
+		case map[string]any:
+			v, ok := node[tok]
+			if !ok {
+				return nil, p[:i+1].notFound()
+			}
+
+			tree = v
+		case []any:
+			j, err := arrayIndex(tok, len(node))
+			if err != nil {
+				return nil, err
+			}
+
+			if j >= len(node) {
+				return nil, p[:i+1].notFound()
+			}
+
+			tree = node[j]
+		default:
+			return nil, p[:i+1].notFound()
+		}
+	}
+
+	return tree, nil
+}
+
+// SetPointerAny sets the value addressed by the JSON Pointer within a tree of decoded JSON values
+// and returns the resulting tree. See SetPointer for the semantics. Objects are modified in place.
+func SetPointerAny(tree any, ptr jsontext.Pointer, val any) (any, error) {
+	return modifyPointerAny(tree, ptr, val, opSet)
+}
+
+// AddPointerAny adds the value within a tree of decoded JSON values and returns the resulting tree.
+// See AddPointer for the semantics. Objects are modified in place.
+func AddPointerAny(tree any, ptr jsontext.Pointer, val any) (any, error) {
+	return modifyPointerAny(tree, ptr, val, opAdd)
+}
+
+// RemovePointerAny removes the value addressed by the JSON Pointer within a tree of decoded JSON values
+// and returns the resulting tree. Objects are modified in place.
+func RemovePointerAny(tree any, ptr jsontext.Pointer) (any, error) {
+	if ptr == "" {
+		return nil, errors.New("cannot remove the whole document")
+	}
+
+	return modifyPointerAny(tree, ptr, nil, opRemove)
+}
+
+func modifyPointerAny(tree any, ptr jsontext.Pointer, val any, op pointerOp) (any, error) {
+	p, err := pointerTokensOf(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.modifyAny(tree, 0, val, op)
+}
+
+// modifyAny applies the operation to tree, which is the value addressed by the first depth tokens of the pointer.
+func (p pointerTokens) modifyAny(tree any, depth int, val any, op pointerOp) (any, error) {
+	if depth == len(p) {
+		return val, nil
+	}
+
+	tok, last := p[depth], depth == len(p)-1
+
+	switch node := tree.(type) {
+	case map[string]any:
+		child, ok := node[tok]
+
+		switch {
+		case !ok && (!last || op == opRemove):
+			return nil, p.notFound()
+		case last && op == opRemove:
+			delete(node, tok)
+		case last:
+			node[tok] = val
+		default:
+			v, err := p.modifyAny(child, depth+1, val, op)
+			if err != nil {
+				return nil, err
+			}
+
+			node[tok] = v
+		}
+
+		return node, nil
+	case []any:
+		i, err := arrayIndex(tok, len(node))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case i > len(node) || (i == len(node) && (!last || op == opRemove)):
+			return nil, p.notFound()
+		case last && op == opRemove:
+			return slices.Delete(node, i, i+1), nil
+		case last && (op == opAdd || i == len(node)):
+			return slices.Insert(node, i, val), nil
+		case last:
+			node[i] = val
+		default:
+			if node[i], err = p.modifyAny(node[i], depth+1, val, op); err != nil {
+				return nil, err
+			}
+		}
+
+		return node, nil
+	default:
+		return nil, p.notFound()
+	}
+}
@@ -0,0 +1,241 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+// rfc6901Doc is the example document of RFC 6901, section 5.
+const rfc6901Doc = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+func TestPointer(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		doc := jsontext.Value(rfc6901Doc)
+
+		for _, ptr := range []jsontext.Pointer{`foo`, `/~`, `/~2`, `/a~`} {
+			if _, err := jsonutil.GetPointer(doc, ptr); err == nil || errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("get %q: expected invalid pointer error, got: %v", ptr, err)
+			}
+
+			if _, err := jsonutil.SetPointer(doc, ptr, jsontext.Value(`1`)); err == nil {
+				t.Fatalf("set %q: expected error", ptr)
+			}
+
+			if _, err := jsonutil.GetPointerAny(map[string]any{}, ptr); err == nil {
+				t.Fatalf("get any %q: expected error", ptr)
+			}
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		doc := jsontext.Value(rfc6901Doc)
+
+		for ptr, want := range map[jsontext.Pointer]string{
+			``:       string(doc),
+			`/foo`:   `["bar", "baz"]`,
+			`/foo/0`: `"bar"`,
+			`/`:      `0`,
+			`/a~1b`:  `1`,
+			`/c%d`:   `2`,
+			`/e^f`:   `3`,
+			`/g|h`:   `4`,
+			`/i\j`:   `5`,
+			`/k"l`:   `6`,
+			`/ `:     `7`,
+			`/m~0n`:  `8`,
+		} {
+			got, err := jsonutil.GetPointer(doc, ptr)
+			if err != nil {
+				t.Fatalf("%q: unexpected error: %v", ptr, err)
+			}
+
+			if string(got) != want {
+				t.Fatalf("%q: want: %s, got: %s", ptr, want, got)
+			}
+		}
+
+		for _, ptr := range []jsontext.Pointer{`/bar`, `/foo/2`, `/foo/-`, `/foo/01`, `/foo/0/x`} {
+			if _, err := jsonutil.GetPointer(doc, ptr); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("%q: expected ErrPointerNotFound, got: %v", ptr, err)
+			}
+		}
+	})
+
+	t.Run("read value", func(t *testing.T) {
+		dec := jsontext.NewDecoder(strings.NewReader(`{"skip":[1,2,{"x":3}],"items":[{"id":1},{"id":2}],"rest":"` +
+			strings.Repeat("x", 1<<16) + `"}`))
+
+		got, err := jsonutil.ReadPointer(dec, "/items/1/id")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(got) != `2` {
+			t.Fatalf("want: 2, got: %s", got)
+		}
+
+		if dec.InputOffset() > 1<<10 {
+			t.Fatalf("expected the rest of the document not to be read, got offset %d", dec.InputOffset())
+		}
+	})
+
+	t.Run("set, add and remove", func(t *testing.T) {
+		doc := jsontext.Value(`{"a":{"b":[1,2,3]},"c":"d"}`)
+
+		for _, tc := range []struct {
+			op   string
+			ptr  jsontext.Pointer
+			val  string
+			want string
+		}{
+			{"set", ``, `[]`, `[]`},
+			{"set", `/c`, `{"e":true}`, `{"a":{"b":[1,2,3]},"c":{"e":true}}`},
+			{"set", `/x`, `null`, `{"a":{"b":[1,2,3]},"c":"d","x":null}`},
+			{"set", `/a/b/1`, `"two"`, `{"a":{"b":[1,"two",3]},"c":"d"}`},
+			{"set", `/a/b/3`, `4`, `{"a":{"b":[1,2,3,4]},"c":"d"}`},
+			{"set", `/a/b/-`, `4`, `{"a":{"b":[1,2,3,4]},"c":"d"}`},
+			{"add", `/a/b/0`, `0`, `{"a":{"b":[0,1,2,3]},"c":"d"}`},
+			{"add", `/a/b/-`, `4`, `{"a":{"b":[1,2,3,4]},"c":"d"}`},
+			{"add", `/a/c`, `4`, `{"a":{"b":[1,2,3],"c":4},"c":"d"}`},
+			{"remove", `/a/b/1`, ``, `{"a":{"b":[1,3]},"c":"d"}`},
+			{"remove", `/a`, ``, `{"c":"d"}`},
+		} {
+			var (
+				got jsontext.Value
+				err error
+			)
+
+			switch tc.op {
+			case "set":
+				got, err = jsonutil.SetPointer(doc, tc.ptr, jsontext.Value(tc.val))
+			case "add":
+				got, err = jsonutil.AddPointer(doc, tc.ptr, jsontext.Value(tc.val))
+			case "remove":
+				got, err = jsonutil.RemovePointer(doc, tc.ptr)
+			}
+
+			if err != nil {
+				t.Fatalf("%s %q: unexpected error: %v", tc.op, tc.ptr, err)
+			}
+
+			if string(got) != tc.want {
+				t.Fatalf("%s %q: want: %s, got: %s", tc.op, tc.ptr, tc.want, got)
+			}
+		}
+
+		if want := `{"a":{"b":[1,2,3]},"c":"d"}`; string(doc) != want {
+			t.Fatalf("expected document to be unchanged, got: %s", doc)
+		}
+
+		for _, ptr := range []jsontext.Pointer{`/x/y`, `/a/b/4`, `/a/b/x`, `/c/d`} {
+			if _, err := jsonutil.SetPointer(doc, ptr, jsontext.Value(`1`)); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("set %q: expected ErrPointerNotFound, got: %v", ptr, err)
+			}
+		}
+
+		for _, ptr := range []jsontext.Pointer{`/x`, `/a/b/3`, `/a/b/-`} {
+			if _, err := jsonutil.RemovePointer(doc, ptr); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("remove %q: expected ErrPointerNotFound, got: %v", ptr, err)
+			}
+		}
+
+		if _, err := jsonutil.RemovePointer(doc, ""); err == nil {
+			t.Fatalf("expected error when removing the whole document")
+		}
+
+		if _, err := jsonutil.SetPointer(doc, "/c", jsontext.Value(`{`)); err == nil {
+			t.Fatalf("expected error for invalid value")
+		}
+
+		if _, err := jsonutil.AddPointer(doc, "/c", jsontext.Value(`{`)); err == nil {
+			t.Fatalf("expected error for invalid value")
+		}
+
+		if _, err := jsonutil.SetPointer(jsontext.Value(`{"a":`), "/a/c", jsontext.Value(`1`)); err == nil {
+			t.Fatalf("expected error for invalid document")
+		}
+	})
+
+	t.Run("any", func(t *testing.T) {
+		var tree any
+		if err := json.Unmarshal([]byte(rfc6901Doc), &tree); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := jsonutil.GetPointerAny(tree, "/foo/1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if got != "baz" {
+			t.Fatalf("want: baz, got: %v", got)
+		}
+
+		if got, err := jsonutil.GetPointerAny(tree, "/m~0n"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if got != 8.0 {
+			t.Fatalf("want: 8, got: %v", got)
+		}
+
+		for _, ptr := range []jsontext.Pointer{`/x`, `/foo/2`, `/foo/x`, `/ /x`} {
+			if _, err := jsonutil.GetPointerAny(tree, ptr); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("get %q: expected ErrPointerNotFound, got: %v", ptr, err)
+			}
+		}
+
+		tree = map[string]any{"a": map[string]any{"b": []any{1, 2, 3}}, "c": "d"}
+
+		var err error
+		for _, step := range []func() (any, error){
+			func() (any, error) { return jsonutil.SetPointerAny(tree, "/a/b/1", "two") },
+			func() (any, error) { return jsonutil.AddPointerAny(tree, "/a/b/0", 0) },
+			func() (any, error) { return jsonutil.AddPointerAny(tree, "/a/b/-", 4) },
+			func() (any, error) { return jsonutil.RemovePointerAny(tree, "/a/b/2") },
+			func() (any, error) { return jsonutil.RemovePointerAny(tree, "/c") },
+			func() (any, error) { return jsonutil.SetPointerAny(tree, "/e", true) },
+		} {
+			if tree, err = step(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		want := map[string]any{"a": map[string]any{"b": []any{0, 1, 3, 4}}, "e": true}
+		if !reflect.DeepEqual(tree, want) {
+			t.Fatalf("want: %v, got: %v", want, tree)
+		}
+
+		if got, err := jsonutil.SetPointerAny(tree, "", 1); err != nil || got != 1 {
+			t.Fatalf("unexpected result: %v, %v", got, err)
+		}
+
+		if _, err := jsonutil.RemovePointerAny(tree, ""); err == nil {
+			t.Fatalf("expected error when removing the whole document")
+		}
+
+		for _, ptr := range []jsontext.Pointer{`/x/y`, `/a/b/5`, `/e/f`} {
+			if _, err := jsonutil.SetPointerAny(tree, ptr, 1); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("set %q: expected ErrPointerNotFound, got: %v", ptr, err)
+			}
+		}
+
+		for _, ptr := range []jsontext.Pointer{`/x`, `/a/b/-`, `/a/b/01`} {
+			if _, err := jsonutil.RemovePointerAny(tree, ptr); !errors.Is(err, jsonutil.ErrPointerNotFound) {
+				t.Fatalf("remove %q: expected ErrPointerNotFound, got: %v", ptr, err)
+			}
+		}
+	})
+}
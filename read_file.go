@@ -3,25 +3,40 @@ package jsonutil
 import (
 	"encoding/json/v2"
 	"errors"
+	"io"
 	"os"
 )
 
 // ReadFile reads a json file and unmarshals it.
+// Errors while unmarshaling are returned as a *FileError.
 func ReadFile[T any](name string, opts ...json.Options) (T, error) {
-	var v T
-
 	f, err := os.Open(name)
 	if err != nil {
+		var v T
 		return v, err
 	}
 
-	if err := json.UnmarshalRead(f, &v, opts...); err != nil {
-		if closeErr := f.Close(); closeErr != nil {
+	return readAndClose[T](name, f, opts...)
+}
+
+// readAndClose unmarshals the content of r and closes it afterwards.
+func readAndClose[T any](name string, r io.ReadCloser, opts ...json.Options) (T, error) {
+	var v T
+
+	data, err := io.ReadAll(r)
+	if err == nil {
+		err = json.Unmarshal(data, &v, opts...)
+	}
+
+	if err != nil {
+		err = newFileError(name, data, err)
+
+		if closeErr := r.Close(); closeErr != nil {
 			return v, errors.Join(err, closeErr)
 		}
 
 		return v, err
 	}
 
-	return v, f.Close()
+	return v, r.Close()
 }
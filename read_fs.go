@@ -0,0 +1,44 @@
+package jsonutil
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"io/fs"
+)
+
+// ReadFS reads a json file from the given file system and unmarshals it.
+func ReadFS[T any](fsys fs.FS, name string, opts ...json.Options) (T, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		var v T
+		return v, err
+	}
+
+	return readAndClose[T](name, f, opts...)
+}
+
+// ReadFSGlob reads all json files in the given file system whose names match the pattern (see fs.Glob)
+// and returns them keyed by their path.
+// Files that fail to read do not stop the others from being read; their errors are joined and returned
+// together with the successfully read values.
+func ReadFSGlob[T any](fsys fs.FS, pattern string, opts ...json.Options) (map[string]T, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]T, len(names))
+
+	var errs []error
+	for _, name := range names {
+		v, err := ReadFS[T](fsys, name, opts...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		m[name] = v
+	}
+
+	return m, errors.Join(errs...)
+}
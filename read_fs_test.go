@@ -0,0 +1,95 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"io/fs"
+	"maps"
+	"path"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestReadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.json":       {Data: []byte(`{"duration":30}`)},
+		"conf.d/a.json":     {Data: []byte(`{"duration":1}`)},
+		"conf.d/b.json":     {Data: []byte(`{"duration":2}`)},
+		"conf.d/readme.txt": {Data: []byte(`not json`)},
+		"broken/a.json":     {Data: []byte(`{"duration":`)},
+		"broken/b.json":     {Data: []byte(`{"duration":"3"}`)},
+		"broken/c.json":     {Data: []byte(`{"duration":3}`)},
+	}
+
+	type config struct {
+		Duration time.Duration `json:"duration"`
+	}
+
+	jsonOpts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds))
+
+	t.Run("single file", func(t *testing.T) {
+		got, err := jsonutil.ReadFS[config](fsys, "config.json", jsonOpts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := (config{30 * time.Second}); got != want {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := jsonutil.ReadFS[config](fsys, "missing.json", jsonOpts); !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected not exist error, got: %v", err)
+		}
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		got, err := jsonutil.ReadFSGlob[config](fsys, "conf.d/*.json", jsonOpts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := map[string]config{
+			"conf.d/a.json": {time.Second},
+			"conf.d/b.json": {2 * time.Second},
+		}
+		if !maps.Equal(got, want) {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("glob errors", func(t *testing.T) {
+		got, err := jsonutil.ReadFSGlob[config](fsys, "broken/*.json", jsonOpts)
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+
+		errSyn := &jsontext.SyntacticError{}
+		if !errors.As(err, &errSyn) {
+			t.Fatalf("expected error to contain a syntactic error, got: %v", err)
+		}
+
+		errSem := &json.SemanticError{}
+		if !errors.As(err, &errSem) {
+			t.Fatalf("expected error to contain a semantic error, got: %v", err)
+		} else if tpInt := reflect.TypeFor[int64](); errSem.GoType != tpInt {
+			t.Fatalf("expected semantic error to have type %s, got: %s", tpInt, errSem.GoType)
+		}
+
+		if want := map[string]config{"broken/c.json": {3 * time.Second}}; !maps.Equal(got, want) {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		if _, err := jsonutil.ReadFSGlob[config](fsys, "[", jsonOpts); !errors.Is(err, path.ErrBadPattern) {
+			t.Fatalf("expected bad pattern error, got: %v", err)
+		}
+	})
+}
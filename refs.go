@@ -0,0 +1,176 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrCircularReference is returned when resolving references leads back to a value that is being resolved.
+var ErrCircularReference = errors.New("circular reference")
+
+// ReadFileWithRefs reads a json file, resolves the references it contains and unmarshals the result.
+//
+// A reference is an object whose only member is either
+//   - "$include" with the name of a file whose content replaces the object, or
+//   - "$ref" with a file name followed by "#" and a JSON Pointer into that file (e.g. "other.json#/a/b"),
+//     whose addressed value replaces the object. If the file name is empty, the pointer addresses the current file.
+//
+// File names are relative to the including file. Pointers address the document before its references are resolved.
+// References in included values are resolved as well.
+func ReadFileWithRefs[T any](name string, opts ...json.Options) (T, error) {
+	r := &refResolver{
+		read: func(name string) (jsontext.Value, error) { return ReadFile[jsontext.Value](name, opts...) },
+		join: func(from, name string) string {
+			if filepath.IsAbs(name) {
+				return name
+			}
+
+			return filepath.Join(filepath.Dir(from), name)
+		},
+	}
+
+	return readWithRefs[T](r, name, opts...)
+}
+
+// ReadFSWithRefs is like ReadFileWithRefs, but reads the files from the given file system.
+func ReadFSWithRefs[T any](fsys fs.FS, name string, opts ...json.Options) (T, error) {
+	r := &refResolver{
+		read: func(name string) (jsontext.Value, error) { return ReadFS[jsontext.Value](fsys, name, opts...) },
+		join: func(from, name string) string { return path.Join(path.Dir(from), name) },
+	}
+
+	return readWithRefs[T](r, name, opts...)
+}
+
+func readWithRefs[T any](r *refResolver, name string, opts ...json.Options) (T, error) {
+	var v T
+
+	r.files = map[string]jsontext.Value{}
+
+	doc, err := r.load(name)
+	if err != nil {
+		return v, err
+	}
+
+	resolved, err := r.resolve(name, doc, []string{name + "#"})
+	if err != nil {
+		return v, err
+	}
+
+	return v, json.Unmarshal(resolved, &v, opts...)
+}
+
+// refResolver resolves "$ref" and "$include" references, caching the files it reads.
+type refResolver struct {
+	read  func(name string) (jsontext.Value, error)
+	join  func(from, name string) string
+	files map[string]jsontext.Value
+}
+
+func (r *refResolver) load(name string) (jsontext.Value, error) {
+	if v, ok := r.files[name]; ok {
+		return v, nil
+	}
+
+	v, err := r.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.files[name] = v
+
+	return v, nil
+}
+
+// resolve returns v with all references replaced. The name is the file v originates from,
+// the stack holds the references that are currently being resolved.
+func (r *refResolver) resolve(name string, v jsontext.Value, stack []string) (jsontext.Value, error) {
+	switch v.Kind() {
+	case jsontext.KindBeginObject:
+		members, err := readMembers(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(members) == 1 {
+			switch m := members[0]; m.name {
+			case "$include", "$ref":
+				return r.resolveRef(name, m.name, m.value, stack)
+			}
+		}
+
+		for i, m := range members {
+			if members[i].value, err = r.resolve(name, m.value, stack); err != nil {
+				return nil, err
+			}
+		}
+
+		return encodeMembers(members)
+	case jsontext.KindBeginArray:
+		var elems []jsontext.Value
+		if err := json.Unmarshal(v, &elems); err != nil {
+			return nil, err
+		}
+
+		for i, elem := range elems {
+			var err error
+			if elems[i], err = r.resolve(name, elem, stack); err != nil {
+				return nil, err
+			}
+		}
+
+		return json.Marshal(elems)
+	default:
+		return v, nil
+	}
+}
+
+func (r *refResolver) resolveRef(from, kind string, target jsontext.Value, stack []string) (jsontext.Value, error) {
+	var ref string
+	if err := json.Unmarshal(target, &ref); err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", kind, from, err)
+	}
+
+	file, ptr := ref, ""
+	if kind == "$ref" {
+		var ok bool
+		if file, ptr, ok = strings.Cut(ref, "#"); !ok {
+			return nil, fmt.Errorf("%s in %s: missing JSON pointer in %q", kind, from, ref)
+		}
+	}
+
+	name := from
+	if file != "" {
+		name = r.join(from, file)
+	}
+
+	key := name + "#" + ptr
+	if i := slices.Index(stack, key); i >= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCircularReference, strings.Join(append(stack[i:], key), " -> "))
+	}
+
+	doc, err := r.load(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", kind, from, err)
+	}
+
+	dec := jsontext.NewDecoder(bytes.NewReader(doc))
+	if err := seekPointer(dec, jsontext.Pointer(ptr)); err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", kind, from, err)
+	}
+
+	v, err := dec.ReadValue()
+	if err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", kind, from, err)
+	}
+
+	return r.resolve(name, v, append(stack, key))
+}
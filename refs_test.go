@@ -0,0 +1,129 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestReadWithRefs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.json": {Data: []byte(`{
+	"name": "main",
+	"db": {"$include": "parts/db.json"},
+	"hosts": [{"$ref": "parts/hosts.json#/primary"}, {"$ref": "#/fallback"}],
+	"fallback": "localhost"
+}`)},
+		"parts/db.json":    {Data: []byte(`{"user": {"$ref": "../shared.json#/users/0"}, "port": 5432}`)},
+		"parts/hosts.json": {Data: []byte(`{"primary": "db.example.com"}`)},
+		"shared.json":      {Data: []byte(`{"users": ["admin", "guest"]}`)},
+		"cycle/a.json":     {Data: []byte(`{"b": {"$include": "b.json"}}`)},
+		"cycle/b.json":     {Data: []byte(`{"a": {"$ref": "a.json#/b"}}`)},
+		"self.json":        {Data: []byte(`{"a": {"$ref": "#"}}`)},
+		"missing.json":     {Data: []byte(`{"a": {"$include": "nope.json"}}`)},
+		"pointer.json":     {Data: []byte(`{"a": {"$ref": "shared.json#/users/5"}}`)},
+		"nofragment.json":  {Data: []byte(`{"a": {"$ref": "shared.json"}}`)},
+		"notstring.json":   {Data: []byte(`{"a": {"$ref": 3}}`)},
+		"siblings.json":    {Data: []byte(`{"a": {"$ref": "#/b", "b": 1}}`)},
+		"broken.json":      {Data: []byte(`{"a": {"$include": "partial.json"}}`)},
+		"partial.json":     {Data: []byte(`{"a":`)},
+	}
+
+	type config struct {
+		Name string `json:"name"`
+		DB   struct {
+			User string `json:"user"`
+			Port int    `json:"port"`
+		} `json:"db"`
+		Hosts []string `json:"hosts"`
+	}
+
+	t.Run("resolve", func(t *testing.T) {
+		got, err := jsonutil.ReadFSWithRefs[config](fsys, "main.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.Name != "main" || got.DB.User != "admin" || got.DB.Port != 5432 ||
+			len(got.Hosts) != 2 || got.Hosts[0] != "db.example.com" || got.Hosts[1] != "localhost" {
+			t.Fatalf("unexpected result: %+v", got)
+		}
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		got, err := jsonutil.ReadFSWithRefs[jsontext.Value](fsys, "parts/db.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"user":"admin","port":5432}`; string(got) != want {
+			t.Fatalf("want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("siblings are not a reference", func(t *testing.T) {
+		got, err := jsonutil.ReadFSWithRefs[jsontext.Value](fsys, "siblings.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"a":{"$ref":"#/b","b":1}}`; string(got) != want {
+			t.Fatalf("want: %s, got: %s", want, got)
+		}
+	})
+
+	for _, tc := range []struct {
+		name  string
+		check func(error) bool
+	}{
+		{"cycle/a.json", func(err error) bool { return errors.Is(err, jsonutil.ErrCircularReference) }},
+		{"self.json", func(err error) bool { return errors.Is(err, jsonutil.ErrCircularReference) }},
+		{"missing.json", func(err error) bool { return errors.Is(err, fs.ErrNotExist) }},
+		{"pointer.json", func(err error) bool { return errors.Is(err, jsonutil.ErrPointerNotFound) }},
+		{"nofragment.json", func(err error) bool { return err != nil }},
+		{"notstring.json", func(err error) bool { return err != nil }},
+		{"broken.json", func(err error) bool {
+			errSyn := &jsontext.SyntacticError{}
+			return errors.As(err, &errSyn)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jsonutil.ReadFSWithRefs[jsontext.Value](fsys, tc.name); !tc.check(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("cycle message", func(t *testing.T) {
+		_, err := jsonutil.ReadFSWithRefs[jsontext.Value](fsys, "cycle/a.json")
+		if want := "circular reference: cycle/b.json# -> cycle/a.json#/b -> cycle/b.json#"; err == nil || err.Error() != want {
+			t.Fatalf("want: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("os", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.CopyFS(dir, fsys); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := jsonutil.ReadFileWithRefs[config](filepath.Join(dir, "main.json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.DB.User != "admin" || got.Hosts[0] != "db.example.com" {
+			t.Fatalf("unexpected result: %+v", got)
+		}
+
+		if _, err := jsonutil.ReadFileWithRefs[config](filepath.Join(dir, "cycle", "a.json")); !errors.Is(err, jsonutil.ErrCircularReference) {
+			t.Fatalf("expected circular reference, got: %v", err)
+		}
+	})
+}
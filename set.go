@@ -0,0 +1,124 @@
+package jsonutil
+
+import (
+	"cmp"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// SetMarshal is a custom marshaler for sets, i.e. maps with empty struct values,
+// marshaling them as arrays of their elements in ascending order, e.g. ["a","b"] instead of {"a":{},"b":{}}.
+func SetMarshal[M ~map[K]struct{}, K cmp.Ordered](enc *jsontext.Encoder, m M) error {
+	return marshalSet(enc, m == nil, sortedKeys(m))
+}
+
+// SetUnmarshal is a custom unmarshaler for sets, i.e. maps with empty struct values,
+// unmarshaling them from arrays of their elements. Duplicate elements are rejected. Nulls unmarshal as nil.
+func SetUnmarshal[M ~map[K]struct{}, K cmp.Ordered](dec *jsontext.Decoder, m *M) error {
+	return unmarshalSet(dec, m, struct{}{})
+}
+
+// BoolSetMarshal is a custom marshaler for sets modeled as maps with bool values,
+// marshaling the keys with a true value as an array in ascending order.
+func BoolSetMarshal[M ~map[K]bool, K cmp.Ordered](enc *jsontext.Encoder, m M) error {
+	return marshalSet(enc, m == nil, slices.DeleteFunc(sortedKeys(m), func(k K) bool { return !m[k] }))
+}
+
+// BoolSetUnmarshal is a custom unmarshaler for sets modeled as maps with bool values,
+// unmarshaling them from arrays of the keys to set to true. Duplicate elements are rejected. Nulls unmarshal as nil.
+func BoolSetUnmarshal[M ~map[K]bool, K cmp.Ordered](dec *jsontext.Decoder, m *M) error {
+	return unmarshalSet(dec, m, true)
+}
+
+func marshalSet[K cmp.Ordered](enc *jsontext.Encoder, isNil bool, elems []K) error {
+	if isNil {
+		return enc.WriteToken(jsontext.Null)
+	}
+
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+
+	for _, e := range elems {
+		if err := json.MarshalEncode(enc, e); err != nil {
+			return err
+		}
+	}
+
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+func unmarshalSet[M ~map[K]V, K cmp.Ordered, V any](dec *jsontext.Decoder, m *M, v V) error {
+	switch kind := dec.PeekKind(); kind {
+	case jsontext.KindNull:
+		*m = nil
+		_, err := dec.ReadToken()
+		return err
+	case jsontext.KindBeginArray:
+	default:
+		return fmt.Errorf("cannot unmarshal JSON %s into a set", kind)
+	}
+
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+
+	set := M{}
+	for dec.PeekKind() != jsontext.KindEndArray {
+		var e K
+		if err := json.UnmarshalDecode(dec, &e); err != nil {
+			return err
+		}
+
+		if _, ok := set[e]; ok {
+			return fmt.Errorf("duplicate set element %v", e)
+		}
+
+		set[e] = v
+	}
+
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+
+	*m = set
+	return nil
+}
+
+// Set is a set of ordered elements that is marshaled as an array of its elements in ascending order
+// and unmarshaled from an array without duplicates, see SetMarshal and SetUnmarshal.
+type Set[K cmp.Ordered] map[K]struct{}
+
+// NewSet returns a set of the given elements.
+func NewSet[K cmp.Ordered](elems ...K) Set[K] {
+	s := make(Set[K], len(elems))
+	for _, e := range elems {
+		s.Add(e)
+	}
+
+	return s
+}
+
+// Add adds the element to the set.
+func (s Set[K]) Add(e K) { s[e] = struct{}{} }
+
+// Has reports whether the element is in the set.
+func (s Set[K]) Has(e K) bool {
+	_, ok := s[e]
+	return ok
+}
+
+// Delete removes the element from the set.
+func (s Set[K]) Delete(e K) { delete(s, e) }
+
+// All returns an iterator over the elements in ascending order.
+func (s Set[K]) All() iter.Seq[K] { return slices.Values(sortedKeys(s)) }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (s Set[K]) MarshalJSONTo(enc *jsontext.Encoder) error { return SetMarshal(enc, s) }
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (s *Set[K]) UnmarshalJSONFrom(dec *jsontext.Decoder) error { return SetUnmarshal(dec, s) }
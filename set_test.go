@@ -0,0 +1,150 @@
+package jsonutil_test
+
+import (
+	"encoding/json/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testTags map[string]struct{}
+
+type testFlags map[string]bool
+
+func TestSet(t *testing.T) {
+	type testItem struct {
+		Tags  testTags  `json:"tags"`
+		Flags testFlags `json:"flags"`
+	}
+
+	opts := json.JoinOptions(
+		json.WithMarshalers(json.JoinMarshalers(
+			json.MarshalToFunc(jsonutil.SetMarshal[testTags]),
+			json.MarshalToFunc(jsonutil.BoolSetMarshal[testFlags]),
+		)),
+		json.WithUnmarshalers(json.JoinUnmarshalers(
+			json.UnmarshalFromFunc(jsonutil.SetUnmarshal[testTags]),
+			json.UnmarshalFromFunc(jsonutil.BoolSetUnmarshal[testFlags]),
+		)),
+	)
+
+	v := testItem{
+		Tags:  testTags{"go": {}, "api": {}, "json": {}},
+		Flags: testFlags{"verified": true, "admin": false, "beta": true},
+	}
+
+	b, err := json.Marshal(v, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"tags":["api","go","json"],"flags":["beta","verified"]}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out testItem
+	if err := json.Unmarshal(b, &out, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(out.Tags) != 3 || len(out.Flags) != 2 || !out.Flags["beta"] || out.Flags["admin"] {
+		t.Fatalf("want: %+v, got: %+v", v, out)
+	}
+
+	t.Run("null and empty", func(t *testing.T) {
+		b, err := json.Marshal(testItem{Flags: testFlags{}}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"tags":null,"flags":[]}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		out := v
+		if err := json.Unmarshal([]byte(`{"tags":null,"flags":[]}`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.Tags != nil || out.Flags == nil || len(out.Flags) != 0 {
+			t.Fatalf("want nil tags and empty flags, got: %+v", out)
+		}
+	})
+
+	t.Run("replaces existing elements", func(t *testing.T) {
+		out := v
+		if err := json.Unmarshal([]byte(`{"tags":["new"]}`), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, ok := out.Tags["new"]; !ok || len(out.Tags) != 1 {
+			t.Fatalf("want: [new], got: %v", out.Tags)
+		}
+	})
+
+	for in, want := range map[string]string{
+		`{"tags":["go","api","go"]}`: `duplicate set element go`,
+		`{"flags":["a","a"]}`:        `duplicate set element a`,
+		`{"tags":{"go":{}}}`:         `into a set`,
+		`{"tags":[1]}`:               `string`,
+	} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out testItem
+			err := json.Unmarshal([]byte(in), &out, opts)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected error to contain %q, got: %v", want, err)
+			}
+		})
+	}
+}
+
+func TestSetType(t *testing.T) {
+	s := jsonutil.NewSet(3, 1, 2)
+	s.Add(5)
+	s.Delete(2)
+
+	if !s.Has(5) || s.Has(2) {
+		t.Fatalf("unexpected set: %v", s)
+	}
+
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Fatalf("want: [1 3 5], got: %v", got)
+	}
+
+	type testPrimes struct {
+		Primes jsonutil.Set[int] `json:"primes"`
+	}
+
+	b, err := json.Marshal(testPrimes{Primes: s})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"primes":[1,3,5]}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out testPrimes
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(out.Primes) != 3 || !out.Primes.Has(3) {
+		t.Fatalf("want: %v, got: %v", s, out.Primes)
+	}
+
+	for _, in := range []string{`{"primes":[1,1]}`, `{"primes":"1"}`} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			var out testPrimes
+			if err := json.Unmarshal([]byte(in), &out); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"io"
+	"os"
+)
+
+// StrictOptions rejects unknown object members, duplicate object names and invalid UTF-8.
+// Trailing data after the top-level value is always rejected when unmarshaling.
+var StrictOptions = json.JoinOptions(
+	json.RejectUnknownMembers(true),
+	jsontext.AllowDuplicateNames(false),
+	jsontext.AllowInvalidUTF8(false),
+)
+
+// ReadFileStrict reads a json file and unmarshals it using StrictOptions on top of the given options.
+// Unlike unmarshaling with StrictOptions, all unknown members are reported, not just the first one.
+// The returned error joins a *FileError for every unknown member, pointing to its name,
+// and for any other error that occurred. Since unmarshaling stops at any other error,
+// unknown members that follow it are not reported. Neither are those that follow an unknown member
+// rejected by a custom unmarshaler calling json.Unmarshal, whose position is unknown.
+func ReadFileStrict[T any](name string, opts ...json.Options) (T, error) {
+	var v T
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return v, err
+	}
+
+	return v, unmarshalStrict(name, data, &v, opts...)
+}
+
+// maxStrictPasses limits how often unmarshalStrict repeats unmarshaling, i.e. how many unknown members it reports.
+const maxStrictPasses = 1000
+
+// unmarshalStrict unmarshals data into v using StrictOptions.
+// Every time an unknown member is encountered, it is removed and unmarshaling is repeated,
+// so that all unknown members are found.
+func unmarshalStrict[T any](name string, data []byte, v *T, opts ...json.Options) error {
+	opts = append(opts, StrictOptions)
+
+	var errs []error
+	for doc, pass := data, 1; ; pass++ {
+		*v = *new(T)
+
+		err := json.Unmarshal(doc, v, opts...)
+		errSem := &json.SemanticError{}
+		if err == nil || !errors.As(err, &errSem) || errSem.Err != json.ErrUnknownName {
+			if len(errs) == 0 {
+				if err != nil {
+					return newFileError(name, data, err)
+				}
+
+				return nil
+			}
+
+			break
+		}
+
+		next, removed, errRemove := removeMember(doc, errSem.JSONPointer)
+		if errRemove != nil {
+			return errRemove
+		}
+
+		// If the member was not found, the pointer and offset are relative to a value that a custom unmarshaler
+		// passed to json.Unmarshal. Report the error as is, without a position, instead of repeating it forever.
+		if !removed || pass == maxStrictPasses {
+			return errors.Join(append(errs, newFileError(name, nil, err))...)
+		}
+
+		unknown := *errSem
+		if offset, ok := memberOffset(data, unknown.JSONPointer); ok {
+			unknown.ByteOffset = offset
+		}
+
+		errs = append(errs, newFileError(name, data, &unknown))
+		doc = next
+	}
+
+	// unmarshal the original data again to report any remaining error with its original position
+	*v = *new(T)
+	if err := json.Unmarshal(data, v, append(opts, json.RejectUnknownMembers(false))...); err != nil {
+		errs = append(errs, newFileError(name, data, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// memberOffset returns the byte offset of the name of the object member addressed by ptr.
+func memberOffset(data []byte, ptr jsontext.Pointer) (int64, bool) {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	if err := seekPointer(dec, ptr.Parent()); err != nil || dec.PeekKind() != jsontext.KindBeginObject {
+		return 0, false
+	}
+
+	if _, err := dec.ReadToken(); err != nil { // consume jsontext.KindBeginObject
+		return 0, false
+	}
+
+	for dec.PeekKind() != jsontext.KindEndObject {
+		offset := dec.InputOffset()
+
+		tkn, err := dec.ReadToken()
+		if err != nil {
+			return 0, false
+		}
+
+		if tkn.String() == ptr.LastToken() {
+			// only whitespace and a comma may precede the name
+			return offset + int64(bytes.IndexByte(data[offset:], '"')), true
+		}
+
+		if err := dec.SkipValue(); err != nil {
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
+
+// removeMember returns a copy of the JSON value without the object member addressed by ptr
+// and reports whether the member was found.
+func removeMember(v jsontext.Value, ptr jsontext.Pointer) (jsontext.Value, bool, error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(v))
+
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+
+	removed := false
+	for {
+		tkn, err := dec.ReadToken()
+		if err == io.EOF {
+			return buf.Bytes(), removed, nil
+		} else if err != nil {
+			return nil, false, err
+		}
+
+		if kind, length := dec.StackIndex(dec.StackDepth()); kind == jsontext.KindBeginObject &&
+			length%2 == 1 && dec.StackPointer() == ptr {
+			if err := dec.SkipValue(); err != nil {
+				return nil, false, err
+			}
+
+			removed = true
+			continue
+		}
+
+		if err := enc.WriteToken(tkn); err != nil {
+			return nil, false, err
+		}
+	}
+}
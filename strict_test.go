@@ -0,0 +1,204 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+// testStrictCustom unmarshals its members with json.Unmarshal, so that errors are relative to its value.
+type testStrictCustom struct {
+	Y int `json:"y"`
+}
+
+func (c *testStrictCustom) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	val, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	type plain testStrictCustom
+	return json.Unmarshal(val, (*plain)(c), dec.Options())
+}
+
+func TestReadFileStrict(t *testing.T) {
+	type server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	type config struct {
+		Name     string        `json:"name"`
+		Duration time.Duration `json:"duration"`
+		Servers  []server      `json:"servers"`
+	}
+
+	jsonOpts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds))
+
+	dir := t.TempDir()
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+
+		name := filepath.Join(dir, filepath.Base(t.Name())+".json")
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return name
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		name := write(t, `{"name":"foo","duration":3,"servers":[{"host":"a","port":1}]}`)
+
+		got, err := jsonutil.ReadFileStrict[config](name, jsonOpts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := config{Name: "foo", Duration: 3 * time.Second, Servers: []server{{"a", 1}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("unknown members", func(t *testing.T) {
+		name := write(t, `{
+  "name": "foo",
+  "nmae": "typo",
+  "servers": [
+    {"host": "a", "prot": 1},
+    {"host": "b", "port": 2, "extra": {"nested": true}}
+  ],
+  "duration": 3
+}`)
+
+		got, err := jsonutil.ReadFileStrict[config](name, jsonOpts)
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+
+		var pointers []jsontext.Pointer
+		var positions [][2]int
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			errFile := &jsonutil.FileError{}
+			if !errors.As(err, &errFile) {
+				t.Fatalf("expected error to be a file error, got: %v", err)
+			}
+
+			errSem := &json.SemanticError{}
+			if !errors.As(err, &errSem) || !errors.Is(err, json.ErrUnknownName) {
+				t.Fatalf("expected error to be an unknown name error, got: %v", err)
+			}
+
+			pointers = append(pointers, errFile.JSONPointer)
+			positions = append(positions, [2]int{errFile.Line, errFile.Column})
+		}
+
+		if want := []jsontext.Pointer{"/nmae", "/servers/0/prot", "/servers/1/extra"}; !reflect.DeepEqual(pointers, want) {
+			t.Fatalf("want: %v, got: %v", want, pointers)
+		}
+
+		if want := [][2]int{{3, 3}, {5, 19}, {6, 30}}; !reflect.DeepEqual(positions, want) {
+			t.Fatalf("want: %v, got: %v", want, positions)
+		}
+
+		// known members are still unmarshaled
+		want := config{Name: "foo", Duration: 3 * time.Second, Servers: []server{{"a", 0}, {"b", 2}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("unknown members and semantic error", func(t *testing.T) {
+		name := write(t, `{"foo":1,"duration":"3","bar":2}`)
+
+		_, err := jsonutil.ReadFileStrict[config](name, jsonOpts)
+
+		errSem := &json.SemanticError{}
+		if !errors.Is(err, json.ErrUnknownName) {
+			t.Fatalf("expected unknown name error, got: %v", err)
+		} else if !errors.As(err, &errSem) {
+			t.Fatalf("expected error to be a semantic error, got: %v", err)
+		}
+
+		// unmarshaling stops at the semantic error, so "bar" is not reported
+		errs := err.(interface{ Unwrap() []error }).Unwrap()
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got: %v", errs)
+		}
+
+		errFile := &jsonutil.FileError{}
+		if !errors.As(errs[1], &errFile) {
+			t.Fatalf("expected error to be a file error, got: %v", errs[1])
+		} else if errFile.JSONPointer != "/duration" || errFile.Column != 21 {
+			t.Fatalf("unexpected file error: %#v", errFile)
+		}
+	})
+
+	t.Run("unknown member in custom unmarshaler", func(t *testing.T) {
+		type outer struct {
+			C testStrictCustom `json:"C"`
+		}
+
+		name := write(t, `{"x":0,"C":{"y":1,"zzz":2}}`)
+
+		_, err := jsonutil.ReadFileStrict[outer](name)
+		if !errors.Is(err, json.ErrUnknownName) {
+			t.Fatalf("expected unknown name error, got: %v", err)
+		}
+
+		errs := err.(interface{ Unwrap() []error }).Unwrap()
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got: %v", errs)
+		}
+
+		for i, want := range []struct {
+			ptr  jsontext.Pointer
+			line int
+		}{{"/x", 1}, {"/zzz", 0}} {
+			errFile := &jsonutil.FileError{}
+			if !errors.As(errs[i], &errFile) {
+				t.Fatalf("expected error to be a file error, got: %v", errs[i])
+			} else if errFile.JSONPointer != want.ptr || errFile.Line != want.line {
+				t.Fatalf("unexpected file error: %#v", errFile)
+			}
+		}
+	})
+
+	for _, tc := range []struct{ name, content string }{
+		{"duplicate", `{"name":"a","name":"b"}`},
+		{"trailing", `{"name":"a"} {}`},
+		{"utf8", "{\"name\":\"\xff\"}"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			name := write(t, tc.content)
+
+			// sanity check: the same options are accepted by ReadFile
+			if tc.name != "trailing" {
+				if _, err := jsonutil.ReadFile[config](name,
+					jsontext.AllowDuplicateNames(true), jsontext.AllowInvalidUTF8(true)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			errSyn := &jsontext.SyntacticError{}
+			if _, err := jsonutil.ReadFileStrict[config](name,
+				jsontext.AllowDuplicateNames(true), jsontext.AllowInvalidUTF8(true)); !errors.As(err, &errSyn) {
+				t.Fatalf("expected error to be a syntactic error, got: %v", err)
+			}
+		})
+	}
+
+	t.Run("not exist", func(t *testing.T) {
+		if _, err := jsonutil.ReadFileStrict[config](filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist error, got: %v", err)
+		}
+	})
+}
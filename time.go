@@ -73,3 +73,62 @@ func TimeUnmarshalStringOrIntUnix(dec *jsontext.Decoder, d *time.Time) error {
 
 	return nil
 }
+
+// TimeMarshalIntUnixMilli is a custom marshaler for time.Time, marshaling them as integers representing unix time in milliseconds.
+func TimeMarshalIntUnixMilli(enc *jsontext.Encoder, t time.Time) error {
+	if t.IsZero() {
+		return enc.WriteToken(jsontext.Int(0))
+	}
+
+	return enc.WriteToken(jsontext.Int(t.UnixMilli()))
+}
+
+// TimeUnmarshalIntUnixMilli is a custom unmarshaler for time.Time, unmarshaling them from integers and assuming they represent unix time in milliseconds.
+func TimeUnmarshalIntUnixMilli(dec *jsontext.Decoder, d *time.Time) error {
+	var ms int64
+	if err := json.UnmarshalDecode(dec, &ms); err != nil {
+		return err
+	}
+
+	if ms == 0 {
+		*d = time.Time{}
+	} else {
+		*d = time.UnixMilli(ms)
+	}
+
+	return nil
+}
+
+// UnixTime is a time.Time that is marshaled as an integer representing unix time,
+// see TimeMarshalIntUnix and TimeUnmarshalIntUnix.
+type UnixTime time.Time
+
+// IsZero reports whether the time is the zero time.
+func (t UnixTime) IsZero() bool { return time.Time(t).IsZero() }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (t UnixTime) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return TimeMarshalIntUnix(enc, time.Time(t))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (t *UnixTime) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return TimeUnmarshalIntUnix(dec, (*time.Time)(t))
+}
+
+// UnixMilliTime is a time.Time that is marshaled as an integer representing unix time in milliseconds,
+// see TimeMarshalIntUnixMilli and TimeUnmarshalIntUnixMilli.
+type UnixMilliTime time.Time
+
+// IsZero reports whether the time is the zero time.
+func (t UnixMilliTime) IsZero() bool { return time.Time(t).IsZero() }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (t UnixMilliTime) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return TimeMarshalIntUnixMilli(enc, time.Time(t))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (t *UnixMilliTime) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return TimeUnmarshalIntUnixMilli(dec, (*time.Time)(t))
+}
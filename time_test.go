@@ -5,12 +5,14 @@ import (
 	"encoding/json/v2"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
 )
 
 type testTime struct {
@@ -203,3 +205,94 @@ func TestStringOrUnixTime(t *testing.T) {
 		})
 	}
 }
+
+var unixTimeCodec = jsontest.Codec[time.Time]{
+	Marshal:   jsonutil.TimeMarshalIntUnix,
+	Unmarshal: jsonutil.TimeUnmarshalIntUnix,
+}
+
+// unixSeconds returns the unix time of t the way TimeMarshalIntUnix encodes it.
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+func TestUnixTimeRoundTrip(t *testing.T) {
+	jsontest.CheckRoundTrip(t, unixTimeCodec, func(r *rand.Rand) time.Time {
+		return time.Unix(r.Int64N(1<<40)-1<<39, 0)
+	}, 1000)
+}
+
+func FuzzUnixTime(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, unixTimeCodec, `0`, `1`, `-1`, `1700000000`, `-62135596800`, `null`, `"0"`)
+}
+
+func FuzzStringOrUnixTime(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, jsontest.Codec[time.Time]{
+		Marshal:   jsonutil.TimeMarshalIntUnix,
+		Unmarshal: jsonutil.TimeUnmarshalStringOrIntUnix,
+		// sub-second precision is lost and 1970-01-01T00:00:00Z is encoded like the zero time
+		Equal: func(a, b time.Time) bool { return unixSeconds(a) == unixSeconds(b) },
+	},
+		`0`, `1700000000`, `null`, `"2024-01-02T03:04:05Z"`, `"2024-01-02T03:04:05.5+01:00"`,
+		`"1970-01-01T00:00:00Z"`, `"Mon Jan 2 2006 15:04:05 MST-0700"`, `true`,
+	)
+}
+
+var unixMilliTimeCodec = jsontest.Codec[time.Time]{
+	Marshal:   jsonutil.TimeMarshalIntUnixMilli,
+	Unmarshal: jsonutil.TimeUnmarshalIntUnixMilli,
+}
+
+func TestUnixMilliTimeRoundTrip(t *testing.T) {
+	jsontest.CheckRoundTrip(t, unixMilliTimeCodec, func(r *rand.Rand) time.Time {
+		return time.UnixMilli(r.Int64N(1<<50) - 1<<49)
+	}, 1000)
+}
+
+func FuzzUnixMilliTime(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, unixMilliTimeCodec, `0`, `1`, `-1`, `1700000000000`, `null`, `"0"`)
+}
+
+func TestTimeTypes(t *testing.T) {
+	type times struct {
+		Unix      jsonutil.UnixTime        `json:"unix"`
+		UnixMilli jsonutil.UnixMilliTime   `json:"unixMilli"`
+		Pointer   *jsonutil.UnixTime       `json:"pointer"`
+		OmitZero  jsonutil.UnixMilliTime   `json:"omitZero,omitzero"`
+		Slice     []jsonutil.UnixMilliTime `json:"slice"`
+	}
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)
+	v := times{
+		Unix:      jsonutil.UnixTime(ts.Truncate(time.Second)),
+		UnixMilli: jsonutil.UnixMilliTime(ts),
+		Slice:     []jsonutil.UnixMilliTime{jsonutil.UnixMilliTime(ts), {}},
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"unix":1704164645,"unixMilli":1704164645006,"pointer":null,"slice":[1704164645006,0]}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out times
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !time.Time(out.Unix).Equal(time.Time(v.Unix)) || !time.Time(out.UnixMilli).Equal(ts) ||
+		out.Pointer != nil || !out.OmitZero.IsZero() || !out.Slice[1].IsZero() {
+		t.Fatalf("want: %+v, got: %+v", v, out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"unix":"3"}`), &out); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,231 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ErrUnknownVariant is returned when unmarshaling a union value whose discriminator is not registered
+// or when marshaling a value whose type is not registered.
+var ErrUnknownVariant = errors.New("unknown union variant")
+
+// Union maps the values of a discriminator member, e.g. "type", to the concrete types
+// of the interface I, so that values of I can be marshaled and unmarshaled as JSON objects, e.g.
+//
+//	events := jsonutil.NewUnion[Event]("type").
+//		Register("order.created", OrderCreated{}).
+//		Register("order.cancelled", &OrderCancelled{})
+//
+//	err := json.Unmarshal(b, &e, events.Options())
+//
+// The discriminator member may appear anywhere in the object. It is owned by the union:
+// it is written when marshaling and not passed on when unmarshaling the concrete type.
+// Register all variants before use; a Union is not safe for concurrent registration.
+type Union[I any] struct {
+	key      string
+	types    map[string]reflect.Type
+	names    map[reflect.Type]string
+	fallback func(jsontext.Value) (I, error)
+}
+
+// NewUnion returns a Union of the interface I that uses the given discriminator member.
+func NewUnion[I any](key string) *Union[I] {
+	if reflect.TypeFor[I]().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("jsonutil: union type %s is not an interface", reflect.TypeFor[I]()))
+	}
+
+	return &Union[I]{
+		key:   key,
+		types: map[string]reflect.Type{},
+		names: map[reflect.Type]string{},
+	}
+}
+
+// Register registers the concrete type of v for the discriminator value name.
+// Values of a pointer type are unmarshaled as a pointer to a new value.
+// It panics if v is nil, the name is already registered, or the type is registered under another name.
+func (u *Union[I]) Register(name string, v I) *Union[I] {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		panic("jsonutil: cannot register nil union variant")
+	}
+
+	if _, ok := u.types[name]; ok {
+		panic(fmt.Sprintf("jsonutil: union variant %q already registered", name))
+	}
+
+	if other, ok := u.names[t]; ok {
+		panic(fmt.Sprintf("jsonutil: type %s already registered as %q", t, other))
+	}
+
+	u.types[name], u.names[t] = t, name
+
+	return u
+}
+
+// WithFallback sets a function that is called with the raw object instead of returning an error
+// if the discriminator is not registered. Values of unregistered types, e.g. those returned by it,
+// are then marshaled as they are, without adding a discriminator.
+func (u *Union[I]) WithFallback(f func(raw jsontext.Value) (I, error)) *Union[I] {
+	u.fallback = f
+	return u
+}
+
+// Options returns JSON options that use the union for values of the interface I.
+// To combine it with other marshalers, join Marshal and Unmarshal with them instead.
+func (u *Union[I]) Options() json.Options {
+	return json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(u.Marshal)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(u.Unmarshal)),
+	)
+}
+
+// Marshal is a custom marshaler for values of the interface I,
+// marshaling them as JSON objects with the discriminator as the first member.
+func (u *Union[I]) Marshal(enc *jsontext.Encoder, v *I) error {
+	rv := reflect.ValueOf(v).Elem()
+	if rv.IsNil() {
+		return enc.WriteToken(jsontext.Null)
+	}
+
+	concrete := rv.Elem()
+
+	name, ok := u.names[concrete.Type()]
+	if !ok {
+		if u.fallback != nil {
+			return json.MarshalEncode(enc, concrete.Interface())
+		}
+
+		return fmt.Errorf("%w: type %s", ErrUnknownVariant, concrete.Type())
+	}
+
+	b, err := json.Marshal(concrete.Interface(), enc.Options())
+	if err != nil {
+		return err
+	}
+
+	if k := jsontext.Value(b).Kind(); k != jsontext.KindBeginObject {
+		return fmt.Errorf("union variant %q: expected a JSON object, got %s", name, k)
+	}
+
+	ms, err := readMembers(b)
+	if err != nil {
+		return err
+	}
+
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+
+	if err := enc.WriteToken(jsontext.String(u.key)); err != nil {
+		return err
+	}
+
+	if err := enc.WriteToken(jsontext.String(name)); err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		if m.name == u.key {
+			continue // the discriminator is owned by the union
+		}
+
+		if err := enc.WriteToken(jsontext.String(m.name)); err != nil {
+			return err
+		}
+
+		if err := enc.WriteValue(m.value); err != nil {
+			return err
+		}
+	}
+
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+// Unmarshal is a custom unmarshaler for values of the interface I, unmarshaling them from JSON objects
+// into the concrete type registered for the discriminator. A JSON null sets the value to nil.
+func (u *Union[I]) Unmarshal(dec *jsontext.Decoder, v *I) error {
+	raw, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	switch raw.Kind() {
+	case jsontext.KindNull:
+		var zero I
+		*v = zero
+		return nil
+	case jsontext.KindBeginObject:
+	default:
+		return fmt.Errorf("cannot unmarshal JSON %s into union", raw.Kind())
+	}
+
+	ms, err := readMembers(raw)
+	if err != nil {
+		return err
+	}
+
+	// find the discriminator and remove it from the object
+	var name string
+	for i, m := range ms {
+		if m.name != u.key {
+			continue
+		}
+
+		if m.value.Kind() != jsontext.KindString {
+			return fmt.Errorf("discriminator %q is not a string", u.key)
+		}
+
+		if err := json.Unmarshal(m.value, &name); err != nil {
+			return err
+		}
+
+		ms = slices.Delete(ms, i, i+1)
+		break
+	}
+
+	t, ok := u.types[name]
+	if !ok {
+		if u.fallback != nil {
+			res, err := u.fallback(raw.Clone())
+			if err != nil {
+				return err
+			}
+
+			*v = res
+			return nil
+		}
+
+		if name == "" {
+			return fmt.Errorf("%w: missing discriminator %q", ErrUnknownVariant, u.key)
+		}
+
+		return fmt.Errorf("%w: %s %q", ErrUnknownVariant, u.key, name)
+	}
+
+	ptr := t.Kind() == reflect.Pointer
+	if ptr {
+		t = t.Elem()
+	}
+
+	stripped, err := encodeMembers(ms)
+	if err != nil {
+		return err
+	}
+
+	nv := reflect.New(t)
+	if err := json.Unmarshal(stripped, nv.Interface(), dec.Options()); err != nil {
+		return fmt.Errorf("union variant %q: %w", name, err)
+	}
+
+	if !ptr {
+		nv = nv.Elem()
+	}
+
+	*v = nv.Interface().(I)
+	return nil
+}
@@ -0,0 +1,226 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testEvent interface{ isTestEvent() }
+
+type testOrderCreated struct {
+	ID    string    `json:"id"`
+	Total int       `json:"total"`
+	At    time.Time `json:"at"`
+}
+
+func (testOrderCreated) isTestEvent() {}
+
+type testOrderCancelled struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (*testOrderCancelled) isTestEvent() {}
+
+type testUnknownEvent struct{ jsontext.Value }
+
+func (testUnknownEvent) isTestEvent() {}
+
+func (e testUnknownEvent) MarshalJSONTo(enc *jsontext.Encoder) error { return enc.WriteValue(e.Value) }
+
+type testEnvelope struct {
+	Event  testEvent   `json:"event"`
+	Events []testEvent `json:"events,omitempty"`
+}
+
+func newTestEvents() *jsonutil.Union[testEvent] {
+	return jsonutil.NewUnion[testEvent]("type").
+		Register("order.created", testOrderCreated{}).
+		Register("order.cancelled", &testOrderCancelled{})
+}
+
+func TestUnion(t *testing.T) {
+	opts := newTestEvents().Options()
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("round trip", func(t *testing.T) {
+		v := testEnvelope{
+			Event:  testOrderCreated{ID: "a", Total: 3, At: ts},
+			Events: []testEvent{&testOrderCancelled{ID: "b"}, nil},
+		}
+
+		b, err := json.Marshal(v, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		const want = `{"event":{"type":"order.created","id":"a","total":3,"at":"2024-01-02T03:04:05Z"},` +
+			`"events":[{"type":"order.cancelled","id":"b"},null]}`
+		if string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var out testEnvelope
+		if err := json.Unmarshal(b, &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if created, ok := out.Event.(testOrderCreated); !ok || created.ID != "a" || !created.At.Equal(ts) {
+			t.Fatalf("want: %+v, got: %#v", v.Event, out.Event)
+		}
+
+		if cancelled, ok := out.Events[0].(*testOrderCancelled); !ok || cancelled.ID != "b" || out.Events[1] != nil {
+			t.Fatalf("want: %+v, got: %#v", v.Events, out.Events)
+		}
+	})
+
+	t.Run("discriminator not first", func(t *testing.T) {
+		var out testEvent
+		if err := json.Unmarshal([]byte(`{"id":"c","reason":"late","type":"order.cancelled"}`), &out,
+			opts, json.RejectUnknownMembers(true)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := (&testOrderCancelled{ID: "c", Reason: "late"}); *out.(*testOrderCancelled) != *want {
+			t.Fatalf("want: %+v, got: %+v", want, out)
+		}
+	})
+
+	t.Run("options of the caller", func(t *testing.T) {
+		events := newTestEvents()
+		opts := json.JoinOptions(
+			json.WithMarshalers(json.JoinMarshalers(
+				json.MarshalToFunc(events.Marshal),
+				json.MarshalToFunc(jsonutil.TimeMarshalIntUnix),
+			)),
+			json.WithUnmarshalers(json.JoinUnmarshalers(
+				json.UnmarshalFromFunc(events.Unmarshal),
+				json.UnmarshalFromFunc(jsonutil.TimeUnmarshalIntUnix),
+			)),
+		)
+
+		b, err := json.Marshal(testEnvelope{Event: testOrderCreated{ID: "a", At: ts}}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"event":{"type":"order.created","id":"a","total":0,"at":1704164645}}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		var out testEnvelope
+		if err := json.Unmarshal(b, &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !out.Event.(testOrderCreated).At.Equal(ts) {
+			t.Fatalf("want: %s, got: %+v", ts, out.Event)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		opts := newTestEvents().WithFallback(func(raw jsontext.Value) (testEvent, error) {
+			return testUnknownEvent{raw}, nil
+		}).Options()
+
+		const in = `{"id":"d","type":"order.shipped"}`
+
+		var out testEvent
+		if err := json.Unmarshal([]byte(in), &out, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if raw, ok := out.(testUnknownEvent); !ok || string(raw.Value) != in {
+			t.Fatalf("want: %s, got: %#v", in, out)
+		}
+
+		b, err := json.Marshal(testEnvelope{Event: out}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"event":` + in + `}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		in   string
+		msg  string
+	}{
+		{"unknown variant", `{"type":"order.shipped"}`, `type "order.shipped"`},
+		{"missing discriminator", `{"id":"a"}`, `missing discriminator "type"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out testEvent
+			err := json.Unmarshal([]byte(tc.in), &out, opts)
+			if !errors.Is(err, jsonutil.ErrUnknownVariant) {
+				t.Fatalf("expected ErrUnknownVariant, got: %v", err)
+			}
+
+			if !strings.Contains(err.Error(), tc.msg) {
+				t.Fatalf("expected error to contain %q, got: %v", tc.msg, err)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name string
+		in   string
+		msg  string
+	}{
+		{"not an object", `[]`, "cannot unmarshal JSON ["},
+		{"discriminator not a string", `{"type":1}`, `discriminator "type" is not a string`},
+		{"invalid variant", `{"type":"order.created","total":"3"}`, `union variant "order.created"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out testEvent
+			if err := json.Unmarshal([]byte(tc.in), &out, opts); err == nil {
+				t.Fatalf("expected error")
+			} else if !strings.Contains(err.Error(), tc.msg) {
+				t.Fatalf("expected error to contain %q, got: %v", tc.msg, err)
+			}
+		})
+	}
+
+	t.Run("unregistered type", func(t *testing.T) {
+		_, err := json.Marshal(testEnvelope{Event: testUnknownEvent{}}, opts)
+		if !errors.Is(err, jsonutil.ErrUnknownVariant) {
+			t.Fatalf("expected ErrUnknownVariant, got: %v", err)
+		}
+	})
+
+	t.Run("variant not an object", func(t *testing.T) {
+		_, err := json.Marshal(testEnvelope{Event: testUnknownEvent{jsontext.Value(`1`)}},
+			jsonutil.NewUnion[testEvent]("type").Register("raw", testUnknownEvent{}).Options())
+		if err == nil || !strings.Contains(err.Error(), "expected a JSON object") {
+			t.Fatalf("expected error, got: %v", err)
+		}
+	})
+
+	t.Run("invalid registration", func(t *testing.T) {
+		for name, register := range map[string]func(){
+			"not an interface": func() { jsonutil.NewUnion[testOrderCreated]("type") },
+			"nil":              func() { jsonutil.NewUnion[testEvent]("type").Register("nil", nil) },
+			"duplicate name":   func() { newTestEvents().Register("order.created", testUnknownEvent{}) },
+			"duplicate type":   func() { newTestEvents().Register("order.new", testOrderCreated{}) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("expected panic")
+					}
+				}()
+
+				register()
+			})
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package jsonutil
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// UnknownMembers captures the members of a JSON object that have no corresponding struct field
+// and re-emits them in their original order after the known ones, e.g.
+//
+//	type Order struct {
+//		ID string `json:"id"`
+//		jsonutil.UnknownMembers
+//	}
+//
+// so that objects can be decoded, modified and encoded again without losing data,
+// e.g. when proxying objects of a newer API version.
+// A struct can only capture unknown members once, i.e. neither embed UnknownMembers twice
+// nor have another field with the `embed` option that is a map or a jsontext.Value.
+type UnknownMembers struct {
+	// Unknown holds the unknown members as a JSON object or is empty if there are none.
+	Unknown jsontext.Value `json:",embed"`
+}
+
+// Len returns the number of unknown members.
+func (u UnknownMembers) Len() int {
+	ms, _ := u.members()
+	return len(ms)
+}
+
+// All returns an iterator over the names and values of the unknown members in their original order.
+func (u UnknownMembers) All() iter.Seq2[string, jsontext.Value] {
+	return func(yield func(string, jsontext.Value) bool) {
+		ms, _ := u.members()
+		for _, m := range ms {
+			if !yield(m.name, m.value) {
+				return
+			}
+		}
+	}
+}
+
+// Get returns the value of the unknown member with the given name and whether it exists.
+func (u UnknownMembers) Get(name string) (jsontext.Value, bool) {
+	ms, _ := u.members()
+	if i := slices.IndexFunc(ms, func(m member) bool { return m.name == name }); i >= 0 {
+		return ms[i].value, true
+	}
+
+	return nil, false
+}
+
+// Set sets the value of the unknown member with the given name, keeping its position if it exists
+// and adding it at the end otherwise. The name must not be that of a known member.
+func (u *UnknownMembers) Set(name string, value jsontext.Value) error {
+	if !value.IsValid() {
+		return fmt.Errorf("invalid JSON value %q", value)
+	}
+
+	ms, err := u.members()
+	if err != nil {
+		return err
+	}
+
+	if i := slices.IndexFunc(ms, func(m member) bool { return m.name == name }); i >= 0 {
+		ms[i].value = value.Clone()
+	} else {
+		ms = append(ms, member{name, value.Clone()})
+	}
+
+	return u.setMembers(ms)
+}
+
+// Delete removes the unknown member with the given name, if any.
+func (u *UnknownMembers) Delete(name string) error {
+	ms, err := u.members()
+	if err != nil {
+		return err
+	}
+
+	return u.setMembers(slices.DeleteFunc(ms, func(m member) bool { return m.name == name }))
+}
+
+func (u UnknownMembers) members() ([]member, error) {
+	if len(u.Unknown) == 0 {
+		return nil, nil
+	}
+
+	return readMembers(u.Unknown)
+}
+
+func (u *UnknownMembers) setMembers(ms []member) error {
+	if len(ms) == 0 {
+		u.Unknown = nil
+		return nil
+	}
+
+	v, err := encodeMembers(ms)
+	if err != nil {
+		return err
+	}
+
+	u.Unknown = v
+	return nil
+}
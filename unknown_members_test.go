@@ -0,0 +1,136 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+type testProxied struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+	jsonutil.UnknownMembers
+}
+
+func TestUnknownMembers(t *testing.T) {
+	const in = `{"zeta":1,"id":"a","nested":{"b":2,"a":1},"total":3,"alpha":[true]}`
+
+	var v testProxied
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v.ID != "a" || v.Total != 3 {
+		t.Fatalf("unexpected known members: %+v", v)
+	}
+
+	if want := `{"zeta":1,"nested":{"b":2,"a":1},"alpha":[true]}`; string(v.Unknown) != want {
+		t.Fatalf("want: %s, got: %s", want, v.Unknown)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		v.Total = 4
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"id":"a","total":4,"zeta":1,"nested":{"b":2,"a":1},"alpha":[true]}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("accessors", func(t *testing.T) {
+		u := v.UnknownMembers
+
+		if u.Len() != 3 {
+			t.Fatalf("want: 3, got: %d", u.Len())
+		}
+
+		var names []string
+		for name := range u.All() {
+			names = append(names, name)
+		}
+
+		if len(names) != 3 || names[0] != "zeta" || names[1] != "nested" || names[2] != "alpha" {
+			t.Fatalf("unexpected order: %v", names)
+		}
+
+		if val, ok := u.Get("nested"); !ok || string(val) != `{"b":2,"a":1}` {
+			t.Fatalf("unexpected value: %s", val)
+		}
+
+		if _, ok := u.Get("id"); ok {
+			t.Fatalf("expected known member to be missing")
+		}
+
+		if err := u.Set("zeta", jsontext.Value(`"z"`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := u.Set("omega", jsontext.Value(`null`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := u.Delete("nested"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"zeta":"z","alpha":[true],"omega":null}`; string(u.Unknown) != want {
+			t.Fatalf("want: %s, got: %s", want, u.Unknown)
+		}
+
+		if err := u.Set("invalid", jsontext.Value(`{`)); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		for _, name := range []string{"zeta", "alpha", "omega"} {
+			if err := u.Delete(name); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if u.Unknown != nil || u.Len() != 0 {
+			t.Fatalf("expected no unknown members, got: %s", u.Unknown)
+		}
+
+		// the original is unchanged
+		if v.Len() != 3 {
+			t.Fatalf("want: 3, got: %d", v.Len())
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		var v testProxied
+		if err := json.Unmarshal([]byte(`{"id":"b"}`), &v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := `{"id":"b","total":0}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+
+		for range v.All() {
+			t.Fatalf("expected no unknown members")
+		}
+	})
+
+	t.Run("with other options", func(t *testing.T) {
+		var v testProxied
+		if err := json.Unmarshal([]byte(in), &v, jsonutil.StrictOptions); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if v.Len() != 3 {
+			t.Fatalf("want: 3, got: %d", v.Len())
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package jsonutil
+
+import (
+	"context"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrLockTimeout is returned when a file lock could not be acquired in time.
+var ErrLockTimeout = errors.New("timed out waiting for file lock")
+
+// DefaultLockTimeout is the time UpdateFile waits for the file lock.
+const DefaultLockTimeout = 10 * time.Second
+
+// UpdateFile reads a json file, lets update modify its content and writes the result back.
+// A missing file is treated as the zero value of T and created.
+// While the update is in progress, an advisory lock is held on a lock file next to the file
+// (name + ".lock"), so concurrent updates by cooperating processes don't overwrite each other.
+// The file is replaced atomically, so readers never see a partially written file.
+// If update returns an error, the file is left untouched.
+// On platforms without advisory file locks, only the atomic replacement is performed.
+func UpdateFile[T any](name string, update func(*T) error, opts ...json.Options) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultLockTimeout)
+	defer cancel()
+
+	return UpdateFileContext(ctx, name, update, opts...)
+}
+
+// UpdateFileContext is like UpdateFile, but waits for the file lock until the context is done.
+func UpdateFileContext[T any](ctx context.Context, name string, update func(*T) error, opts ...json.Options) (err error) {
+	unlock, err := lockFile(ctx, name+".lock")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil {
+			err = errors.Join(err, unlockErr)
+		}
+	}()
+
+	v, err := ReadFile[T](name, opts...)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := update(&v); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(name, v, opts...)
+}
+
+// lockFileWithRetry acquires a lock with tryLock, retrying until the context is done.
+func lockFileWithRetry(ctx context.Context, name string, tryLock func() (bool, error)) error {
+	const maxDelay = 100 * time.Millisecond
+
+	for delay := time.Millisecond; ; delay = min(2*delay, maxDelay) {
+		if ok, err := tryLock(); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s: %w", ErrLockTimeout, name, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// writeFileAtomic writes a json file by marshalling it into a temporary file that then replaces the file.
+func writeFileAtomic[T any](name string, data T, opts ...json.Options) error {
+	perm := fs.FileMode(0o644)
+	if info, err := os.Stat(name); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if err := writeAndClose(f, data, perm, opts...); err != nil {
+		if rmErr := os.Remove(f.Name()); rmErr != nil {
+			return errors.Join(err, rmErr)
+		}
+
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
+
+func writeAndClose[T any](f *os.File, data T, perm fs.FileMode, opts ...json.Options) error {
+	err := json.MarshalWrite(f, data, opts...)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); closeErr != nil {
+		return errors.Join(err, closeErr)
+	}
+
+	return err
+}
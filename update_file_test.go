@@ -0,0 +1,132 @@
+package jsonutil_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestUpdateFile(t *testing.T) {
+	type state struct {
+		Counter int           `json:"counter"`
+		Elapsed time.Duration `json:"elapsed"`
+	}
+
+	jsonOpts := json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(jsonutil.DurationMarshalIntSeconds)),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds)),
+	)
+
+	name := filepath.Join(t.TempDir(), "state.json")
+
+	t.Run("missing file", func(t *testing.T) {
+		if err := jsonutil.UpdateFile(name, func(s *state) error {
+			if *s != (state{}) {
+				t.Fatalf("expected zero value, got: %v", *s)
+			}
+
+			s.Elapsed = time.Minute
+			return nil
+		}, jsonOpts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"counter":0,"elapsed":60}`; string(b) != want {
+			t.Fatalf("want: %s, got: %s", want, b)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		const n = 20
+
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for range n {
+			wg.Go(func() {
+				errs <- jsonutil.UpdateFile(name, func(s *state) error {
+					s.Counter++
+					return nil
+				}, jsonOpts)
+			})
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		got, err := jsonutil.ReadFile[state](name, jsonOpts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := (state{Counter: n, Elapsed: time.Minute}); got != want {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		if err := jsonutil.UpdateFile(name, func(s *state) error {
+			s.Counter = -1
+			return errAbort
+		}, jsonOpts); !errors.Is(err, errAbort) {
+			t.Fatalf("expected abort error, got: %v", err)
+		}
+
+		if got, err := jsonutil.ReadFile[state](name, jsonOpts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if got.Counter != 20 {
+			t.Fatalf("expected file to be untouched, got: %v", got)
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		broken := filepath.Join(t.TempDir(), "broken.json")
+		if err := os.WriteFile(broken, []byte(`{"counter":`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		errSyn := &jsontext.SyntacticError{}
+		if err := jsonutil.UpdateFile(broken, func(*state) error {
+			t.Fatalf("update must not be called")
+			return nil
+		}); !errors.As(err, &errSyn) {
+			t.Fatalf("expected error to be a syntactic error, got: %v", err)
+		}
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		if err := jsonutil.UpdateFile(name, func(m *map[string]any) error {
+			(*m)["bad"] = make(chan int)
+			return nil
+		}); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		entries, err := os.ReadDir(filepath.Dir(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 2 { // state.json and state.json.lock
+			t.Fatalf("expected temporary file to be removed, got: %v", entries)
+		}
+	})
+}
@@ -34,3 +34,19 @@ func URLUnmarshal(dec *jsontext.Decoder, u *url.URL) error {
 		return fmt.Errorf("expected string, got %s", tkn)
 	}
 }
+
+// JSONURL is a url.URL that is marshaled as a string, see URLMarshal and URLUnmarshal.
+type JSONURL url.URL
+
+// String returns the URL as a string.
+func (u JSONURL) String() string { return (*url.URL)(&u).String() }
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (u JSONURL) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return URLMarshal(enc, url.URL(u))
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (u *JSONURL) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return URLUnmarshal(dec, (*url.URL)(u))
+}
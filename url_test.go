@@ -4,12 +4,15 @@ import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"errors"
+	"math/rand/v2"
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/MarkRosemaker/jsonutil"
+	"github.com/MarkRosemaker/jsonutil/jsontest"
 )
 
 func TestURL(t *testing.T) {
@@ -127,3 +130,73 @@ func TestURL(t *testing.T) {
 		})
 	}
 }
+
+var urlCodec = jsontest.Codec[url.URL]{
+	Marshal:   jsonutil.URLMarshal,
+	Unmarshal: jsonutil.URLUnmarshal,
+	// distinct URL values can have the same string representation
+	Equal: func(a, b url.URL) bool { return a.String() == b.String() },
+}
+
+func TestURLRoundTrip(t *testing.T) {
+	const chars = "abcXYZ019-._~:/?#[]@!$&'()*+,;=% "
+
+	jsontest.CheckRoundTrip(t, urlCodec, func(r *rand.Rand) url.URL {
+		var sb strings.Builder
+		for range r.IntN(8) {
+			sb.WriteByte(chars[r.IntN(len(chars))])
+		}
+
+		return url.URL{
+			Scheme:   []string{"", "http", "https"}[r.IntN(3)],
+			Host:     []string{"example.com", "example.com:8080", "[::1]"}[r.IntN(3)],
+			Path:     "/" + sb.String(),
+			RawQuery: url.Values{"q": {sb.String()}}.Encode(),
+			Fragment: sb.String(),
+		}
+	}, 1000)
+}
+
+func FuzzURL(f *testing.F) {
+	jsontest.FuzzRoundTrip(f, urlCodec,
+		`"https://example.com/path?q=1#frag"`, `"mailto:user@example.org"`, `"/relative/path"`,
+		`"http://[::1]:80/%2F"`, `""`, `null`, `3`,
+	)
+}
+
+func TestJSONURL(t *testing.T) {
+	type links struct {
+		Link    jsonutil.JSONURL   `json:"link"`
+		Pointer *jsonutil.JSONURL  `json:"pointer"`
+		Slice   []jsonutil.JSONURL `json:"slice"`
+	}
+
+	u := jsonutil.JSONURL{Scheme: "https", Host: "example.com", Path: "/path"}
+	v := links{Link: u, Pointer: &u, Slice: []jsonutil.JSONURL{u}}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"link":"https://example.com/path","pointer":"https://example.com/path","slice":["https://example.com/path"]}`; string(b) != want {
+		t.Fatalf("want: %s, got: %s", want, b)
+	}
+
+	var out links
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(out, v) {
+		t.Fatalf("want: %+v, got: %+v", v, out)
+	}
+
+	if got := u.String(); got != "https://example.com/path" {
+		t.Fatalf("want: https://example.com/path, got: %s", got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"link":" http://example.org"}`), &out); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,250 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// member is a member of a JSON object.
+type member struct {
+	name  string
+	value jsontext.Value
+}
+
+// readMembers returns the members of a JSON object in their original order.
+func readMembers(v jsontext.Value) ([]member, error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(v))
+	if _, err := dec.ReadToken(); err != nil { // consume jsontext.KindBeginObject
+		return nil, err
+	}
+
+	var members []member
+	for dec.PeekKind() != jsontext.KindEndObject {
+		tkn, err := dec.ReadToken()
+		if err != nil {
+			return nil, err
+		}
+
+		name := tkn.String()
+
+		val, err := dec.ReadValue()
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, member{name, val.Clone()})
+	}
+
+	return members, nil
+}
+
+// encodeMembers encodes the members as a JSON object, keeping their order.
+func encodeMembers(members []member) (jsontext.Value, error) {
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return nil, err
+	}
+
+	for _, m := range members {
+		if err := enc.WriteToken(jsontext.String(m.name)); err != nil {
+			return nil, err
+		}
+
+		if err := enc.WriteValue(m.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := enc.WriteToken(jsontext.EndObject); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// equalValues reports whether two JSON values are semantically equal: object members are compared
+// regardless of their order, strings after unescaping and numbers by their exact value, e.g. 1 equals 1.0,
+// but 9007199254740993 does not equal 9007199254740992. Invalid values are not equal to anything.
+func equalValues(a, b jsontext.Value) bool {
+	ka, errA := semanticKey(a)
+	kb, errB := semanticKey(b)
+	return errA == nil && errB == nil && ka == kb
+}
+
+// semanticKey returns a text that is the same for two JSON values if and only if they are semantically equal,
+// see equalValues.
+func semanticKey(v jsontext.Value) (string, error) {
+	b, err := appendSemanticKey(nil, v)
+	return string(b), err
+}
+
+// semanticKeys returns the semantic keys of the values.
+func semanticKeys(vs []jsontext.Value) ([]string, error) {
+	keys := make([]string, len(vs))
+	for i, v := range vs {
+		var err error
+		if keys[i], err = semanticKey(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func appendSemanticKey(b []byte, v jsontext.Value) ([]byte, error) {
+	switch v.Kind() {
+	case jsontext.KindBeginObject:
+		members, err := readMembers(v)
+		if err != nil {
+			return nil, err
+		}
+
+		slices.SortFunc(members, func(a, b member) int { return strings.Compare(a.name, b.name) })
+
+		b = append(b, '{')
+		for i, m := range members {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if b, err = jsontext.AppendQuote(b, m.name); err != nil {
+				return nil, err
+			}
+
+			b = append(b, ':')
+			if b, err = appendSemanticKey(b, m.value); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(b, '}'), nil
+	case jsontext.KindBeginArray:
+		var elems []jsontext.Value
+		if err := json.Unmarshal(v, &elems); err != nil {
+			return nil, err
+		}
+
+		b = append(b, '[')
+		for i, elem := range elems {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			var err error
+			if b, err = appendSemanticKey(b, elem); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(b, ']'), nil
+	case jsontext.KindString:
+		return appendStringKey(b, v)
+	case jsontext.KindNumber:
+		return appendNumberKey(b, v)
+	default: // literals
+		switch lit := bytes.TrimSpace(v); string(lit) {
+		case "null", "true", "false":
+			return append(b, lit...), nil
+		default:
+			return nil, fmt.Errorf("invalid JSON value %q", v)
+		}
+	}
+}
+
+// appendStringKey appends the JSON string v with a canonical escaping, so that e.g. "\u0078" and "x" have the same key.
+func appendStringKey(b []byte, v jsontext.Value) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(v, &s); err != nil {
+		return nil, err
+	}
+
+	return jsontext.AppendQuote(b, s)
+}
+
+// appendNumberKey appends the exact value of the JSON number v, so that e.g. 1, 1.0 and 1e0 have the same key.
+// Unlike the canonicalization of RFC 8785, it doesn't round numbers to float64.
+func appendNumberKey(b []byte, v jsontext.Value) ([]byte, error) {
+	r, ok := new(big.Rat).SetString(string(bytes.TrimSpace(v)))
+	if !ok {
+		return nil, fmt.Errorf("invalid JSON number %q", v)
+	}
+
+	return append(b, r.RatString()...), nil
+}
+
+// editOp is an operation of an edit script that transforms one sequence into another.
+type editOp int
+
+const (
+	editMatch editOp = iota
+	editSubstitute
+	editDelete
+	editInsert
+)
+
+// edit is an edit operation on the i-th element of the first and the j-th element of the second sequence.
+type edit struct {
+	op   editOp
+	i, j int
+}
+
+// alignSequences returns a shortest edit script that transforms a sequence of length n into a sequence of length m,
+// where equal reports whether the i-th element of the first sequence equals the j-th element of the second one.
+// The edits are returned from the end of the sequences to their start, so that they can be applied in order
+// without invalidating the indices of the edits that follow.
+func alignSequences(n, m int, equal func(i, j int) bool) []edit {
+	// dist[i][j] is the edit distance between the first i elements of the first and the first j elements of the second sequence
+	dist := make([][]int, n+1)
+	for i := range dist {
+		dist[i] = make([]int, m+1)
+		dist[i][0] = i
+	}
+
+	for j := range dist[0] {
+		dist[0][j] = j
+	}
+
+	eq := make([][]bool, n)
+	for i := range eq {
+		eq[i] = make([]bool, m)
+		for j := range eq[i] {
+			eq[i][j] = equal(i, j)
+		}
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			if eq[i-1][j-1] {
+				dist[i][j] = dist[i-1][j-1]
+			} else {
+				dist[i][j] = 1 + min(dist[i-1][j-1], dist[i-1][j], dist[i][j-1])
+			}
+		}
+	}
+
+	var edits []edit
+	for i, j := n, m; i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && eq[i-1][j-1]:
+			edits = append(edits, edit{editMatch, i - 1, j - 1})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			edits = append(edits, edit{editSubstitute, i - 1, j - 1})
+			i, j = i-1, j-1
+		case i > 0 && dist[i][j] == dist[i-1][j]+1:
+			edits = append(edits, edit{editDelete, i - 1, j})
+			i--
+		default:
+			edits = append(edits, edit{editInsert, i, j - 1})
+			j--
+		}
+	}
+
+	return edits
+}
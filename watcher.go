@@ -0,0 +1,167 @@
+package jsonutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json/v2"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher holds the content of a json file and reloads it whenever the file changes.
+// Changes are detected by polling the modification time and size of the file,
+// and confirmed by comparing a hash of its content.
+// If a reload fails, either because the file can't be read or unmarshaled or because
+// validation fails, the last good value is kept.
+type Watcher[T any] struct {
+	name     string
+	validate func(T) error
+	opts     []json.Options
+
+	value atomic.Pointer[T]
+	err   atomic.Pointer[error]
+
+	mu      sync.Mutex // guards the fields below
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+	subs    map[chan T]struct{}
+}
+
+// NewWatcher reads a json file and returns a Watcher holding its content.
+// The validate function is optional and is called with every newly read value before it is published.
+// An error is returned if the initial value can't be read or is not valid.
+func NewWatcher[T any](name string, validate func(T) error, opts ...json.Options) (*Watcher[T], error) {
+	w := &Watcher[T]{
+		name:     name,
+		validate: validate,
+		opts:     opts,
+		subs:     map[chan T]struct{}{},
+	}
+
+	if _, err := w.Reload(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Load returns the current value.
+func (w *Watcher[T]) Load() T { return *w.value.Load() }
+
+// Err returns the error of the last reload, or nil if it succeeded.
+func (w *Watcher[T]) Err() error {
+	if err := w.err.Load(); err != nil {
+		return *err
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel that receives every new value.
+// Slow subscribers only receive the latest value, intermediate values are dropped.
+// The returned function cancels the subscription and closes the channel.
+func (w *Watcher[T]) Subscribe() (<-chan T, func()) {
+	ch := make(chan T, 1)
+
+	w.mu.Lock()
+	w.subs[ch] = struct{}{}
+	w.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			delete(w.subs, ch)
+			w.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Run polls the file at the given interval until the context is canceled.
+// Errors during a reload are not returned but can be retrieved via Err.
+func (w *Watcher[T]) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_, _ = w.Reload()
+		}
+	}
+}
+
+// Reload checks the file for changes and, if it changed, reads, validates and publishes the new value.
+// It reports whether a new value was published.
+func (w *Watcher[T]) Reload() (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	v, err := w.reload()
+	if err != nil {
+		w.err.Store(&err)
+		return false, err
+	}
+
+	// the error is cleared before the value is published, so that subscribers never see a stale error
+	w.err.Store(nil)
+	if v == nil {
+		return false, nil
+	}
+
+	w.value.Store(v)
+
+	for ch := range w.subs {
+		select { // drop a value the subscriber has not received yet
+		case <-ch:
+		default:
+		}
+
+		ch <- *v
+	}
+
+	return true, nil
+}
+
+// reload reads and validates the file and returns the new value, or nil if the file didn't change.
+func (w *Watcher[T]) reload() (*T, error) {
+	info, err := os.Stat(w.name)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.value.Load() != nil && info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(w.name)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	if w.value.Load() != nil && hash == w.hash {
+		w.modTime, w.size = info.ModTime(), info.Size()
+		return nil, nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v, w.opts...); err != nil {
+		return nil, err
+	}
+
+	if w.validate != nil {
+		if err := w.validate(v); err != nil {
+			return nil, err
+		}
+	}
+
+	w.modTime, w.size, w.hash = info.ModTime(), info.Size(), hash
+	return &v, nil
+}
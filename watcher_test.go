@@ -0,0 +1,164 @@
+package jsonutil_test
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarkRosemaker/jsonutil"
+)
+
+func TestWatcher(t *testing.T) {
+	type config struct {
+		Name    string        `json:"name"`
+		Timeout time.Duration `json:"timeout"`
+	}
+
+	jsonOpts := json.WithUnmarshalers(json.UnmarshalFromFunc(jsonutil.DurationUnmarshalIntSeconds))
+
+	errNoName := errors.New("name is required")
+	validate := func(c config) error {
+		if c.Name == "" {
+			return errNoName
+		}
+
+		return nil
+	}
+
+	name := filepath.Join(t.TempDir(), "config.json")
+	write := func(t *testing.T, content string) {
+		t.Helper()
+
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := jsonutil.NewWatcher(name, validate, jsonOpts); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist error, got: %v", err)
+		}
+	})
+
+	t.Run("invalid initial value", func(t *testing.T) {
+		write(t, `{"timeout":1}`)
+
+		if _, err := jsonutil.NewWatcher(name, validate, jsonOpts); !errors.Is(err, errNoName) {
+			t.Fatalf("expected validation error, got: %v", err)
+		}
+	})
+
+	write(t, `{"name":"a","timeout":1}`)
+
+	w, err := jsonutil.NewWatcher(name, validate, jsonOpts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := w.Load(), (config{"a", time.Second}); got != want {
+		t.Fatalf("want: %v, got: %v", want, got)
+	}
+
+	ch, cancel := w.Subscribe()
+	defer cancel()
+
+	t.Run("unchanged", func(t *testing.T) {
+		if changed, err := w.Reload(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if changed {
+			t.Fatalf("expected no change")
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		write(t, `{"name":"bb","timeout":2}`)
+
+		if changed, err := w.Reload(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if !changed {
+			t.Fatalf("expected change")
+		}
+
+		want := config{"bb", 2 * time.Second}
+		if got := w.Load(); got != want {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+
+		if got := <-ch; got != want {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("syntax error keeps last good value", func(t *testing.T) {
+		write(t, `{"name":"ccc","timeout":`)
+
+		errSyn := &jsontext.SyntacticError{}
+		if _, err := w.Reload(); !errors.As(err, &errSyn) {
+			t.Fatalf("expected syntactic error, got: %v", err)
+		} else if !errors.As(w.Err(), &errSyn) {
+			t.Fatalf("expected Err to return syntactic error, got: %v", w.Err())
+		}
+
+		if got, want := w.Load(), (config{"bb", 2 * time.Second}); got != want {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("validation error keeps last good value", func(t *testing.T) {
+		write(t, `{"name":"","timeout":3}`)
+
+		if _, err := w.Reload(); !errors.Is(err, errNoName) {
+			t.Fatalf("expected validation error, got: %v", err)
+		}
+
+		if got, want := w.Load(), (config{"bb", 2 * time.Second}); got != want {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("run", func(t *testing.T) {
+		ctx, stop := context.WithCancel(t.Context())
+		done := make(chan error)
+		go func() { done <- w.Run(ctx, time.Millisecond) }()
+
+		write(t, `{"name":"dddd","timeout":4}`)
+
+		select {
+		case got := <-ch:
+			if want := (config{"dddd", 4 * time.Second}); got != want {
+				t.Fatalf("want: %v, got: %v", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for reload")
+		}
+
+		if err := w.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		stop()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled, got: %v", err)
+		}
+	})
+
+	t.Run("cancel subscription", func(t *testing.T) {
+		cancel()
+		cancel() // idempotent
+
+		if _, ok := <-ch; ok {
+			t.Fatalf("expected closed channel")
+		}
+
+		write(t, `{"name":"eeeee","timeout":5}`)
+
+		if _, err := w.Reload(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
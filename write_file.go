@@ -7,6 +7,7 @@ import (
 )
 
 // WriteFile writes a json file by marshalling it.
+// Errors while marshaling are returned as a *FileError.
 func WriteFile[T any](name string, data T, opts ...json.Options) error {
 	f, err := os.Create(name)
 	if err != nil {
@@ -14,11 +15,13 @@ func WriteFile[T any](name string, data T, opts ...json.Options) error {
 	}
 
 	if err := json.MarshalWrite(f, data, opts...); err != nil {
-		if closeErr := f.Close(); closeErr != closeErr {
+		err = newFileError(name, nil, err)
+
+		if closeErr := f.Close(); closeErr != nil {
 			return errors.Join(err, closeErr)
 		}
 
-		if rmErr := os.Remove(name); rmErr != rmErr {
+		if rmErr := os.Remove(name); rmErr != nil {
 			return errors.Join(err, rmErr)
 		}
 